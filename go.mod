@@ -3,7 +3,9 @@ module github.com/kozmod/progen
 go 1.22
 
 require (
+	github.com/bmatcuk/doublestar/v4 v4.10.2
 	github.com/go-resty/resty/v2 v2.16.2
+	github.com/pelletier/go-toml/v2 v2.4.3
 	github.com/stretchr/testify v1.10.0
 	go.uber.org/zap v1.27.0
 	golang.org/x/sync v0.10.0
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+
+	"golang.org/x/xerrors"
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+const (
+	// AnswersFile is the file of the resolved variables which is saved to the working directory after the execution.
+	AnswersFile = ".progen-answers.yml"
+
+	answersHeader = "# variables resolved by progen (prompts answers, `-tvarfile` and `-tvar` variables)\n" +
+		"# re-apply: progen -answers=" + AnswersFile + "\n"
+)
+
+// answersFileMode is the mode of the answers file: the answers can contain the secrets of the prompts.
+const answersFileMode os.FileMode = 0o600
+
+// Answers returns the variables tree of the prompts answers and the variables set by flags,
+// which values are resolved from the template data.
+func Answers(templateData map[string]any, prompts Prompts, vars map[string]any) map[string]any {
+	answers := make(map[string]any)
+	for _, prompt := range prompts {
+		if val, ok := entity.LookupVar(templateData, prompt.Path); ok {
+			answers = entity.SetVar(answers, prompt.Path, val)
+		}
+	}
+	return entity.MergeKeys(answers, vars)
+}
+
+// ReadAnswers reads the variables tree of the answers file.
+func ReadAnswers(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, xerrors.Errorf("read answers [%s]: %w", path, err)
+	}
+	var answers map[string]any
+	if err = yaml.Unmarshal(data, &answers); err != nil {
+		return nil, xerrors.Errorf("unmarshal answers [%s]: %w", path, withYamlPosition(err))
+	}
+	return answers, nil
+}
+
+// WriteAnswers writes the variables tree to the answers file, which is readable only by the owner.
+func WriteAnswers(path string, answers map[string]any) error {
+	var doc yaml.Node
+	if err := doc.Encode(answers); err != nil {
+		return xerrors.Errorf("marshal answers: %w", err)
+	}
+	data, err := encodeDocument(&doc)
+	if err != nil {
+		return xerrors.Errorf("marshal answers: %w", err)
+	}
+	if err = os.WriteFile(path, append([]byte(answersHeader), data...), answersFileMode); err != nil {
+		return xerrors.Errorf("write answers [%s]: %w", path, err)
+	}
+	// the mode of the existing file is not changed by writing
+	if err = os.Chmod(path, answersFileMode); err != nil {
+		return xerrors.Errorf("write answers [%s]: %w", path, err)
+	}
+	return nil
+}
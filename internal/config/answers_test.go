@@ -0,0 +1,52 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Answers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success_resolve_prompts_and_vars", func(t *testing.T) {
+		var (
+			templateData = map[string]any{
+				"vars": map[string]any{"name": "svc", "port": 8080, "env": "dev"},
+				"dirs": []any{"a"},
+			}
+			prompts = Prompts{{Path: ".vars.name"}, {Path: ".vars.port"}, {Path: ".vars.missing"}}
+			vars    = map[string]any{"vars": map[string]any{"port": "9090"}, "other": map[string]any{"a": "b"}}
+		)
+		answers := Answers(templateData, prompts, vars)
+		assert.Equal(t, map[string]any{
+			"vars":  map[string]any{"name": "svc", "port": "9090"},
+			"other": map[string]any{"a": "b"},
+		}, answers)
+	})
+	t.Run("success_write_and_read", func(t *testing.T) {
+		var (
+			path    = filepath.Join(t.TempDir(), AnswersFile)
+			answers = map[string]any{"vars": map[string]any{"name": "svc", "port": 8080, "debug": true, "dbs": []any{"pg"}}}
+		)
+		assert.NoError(t, WriteAnswers(path, answers))
+
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), answersHeader)
+
+		info, err := os.Stat(path)
+		assert.NoError(t, err)
+		assert.Equal(t, answersFileMode, info.Mode().Perm())
+
+		res, err := ReadAnswers(path)
+		assert.NoError(t, err)
+		assert.Equal(t, answers, res)
+	})
+	t.Run("error_when_file_not_exists", func(t *testing.T) {
+		_, err := ReadAnswers(filepath.Join(t.TempDir(), AnswersFile))
+		assert.Error(t, err)
+	})
+}
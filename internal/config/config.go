@@ -1,9 +1,14 @@
 package config
 
 import (
+	"io/fs"
 	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/bmatcuk/doublestar/v4"
 	"golang.org/x/xerrors"
 	"gopkg.in/yaml.v3"
 
@@ -16,87 +21,237 @@ const (
 	TagFiles     = "files"
 	TagCmd       = "cmd"
 	TagFS        = "fs"
+	TagLinks     = "links"
+	TagCopy      = "copy"
 	SettingsHTTP = "settings"
+
+	DefaultVarsNamespace = "vars"
+
+	// TemplateDataNamespace is the template data key which contains `progen` execution data.
+	TemplateDataNamespace = "progen"
+	TemplateDataProfiles  = "profiles"
+	TemplateDataFailure   = "failure"
+	TemplateDataAction    = "action"
+	TemplateDataError     = "error"
 )
 
 type Config struct {
 	Settings Settings             `yaml:"settings"`
-	Dirs     []Section[[]string]  `yaml:"dirs,flow"`
+	Dirs     []Section[[]Dir]     `yaml:"dirs,flow"`
 	Rm       []Section[[]string]  `yaml:"rm,flow"`
 	Files    []Section[[]File]    `yaml:"files,flow"`
 	Cmd      []Section[[]Command] `yaml:"cmd,flow"`
-	FS       []Section[[]string]  `yaml:"fs,flow"`
+	FS       []Section[[]Fs]      `yaml:"fs,flow"`
+	Links    []Section[[]Link]    `yaml:"links,flow"`
+	Copy     []Section[[]Copy]    `yaml:"copy,flow"`
 }
 
-func (c Config) CommandActions() []entity.Action[[]entity.Command] {
-	return toActionsSlice(c.Cmd, func(cmd Command) entity.Command {
-		return entity.Command{
-			Cmd:  cmd.Exec,
-			Args: cmd.Args,
-			Dir:  cmd.Dir,
+func (c Config) CommandActions(expander *Expander) ([]entity.Action[[]entity.Command], error) {
+	return toActionsSlice(c.Cmd, func(cmd Command) ([]entity.Command, error) {
+		commands, err := expander.commands(cmd)
+		if err != nil {
+			return nil, newSourceError(cmd.Pos, err)
 		}
+		return toCommands(commands), nil
 	})
 }
 
-func (c Config) FilesActions() []entity.Action[[]entity.UndefinedFile] {
-	return toActionsSlice(c.Files, func(file File) entity.UndefinedFile {
-		uFile := entity.UndefinedFile{
-			Path: file.Path,
-		}
-		if file.Data != nil {
-			*uFile.Data = *file.Data
+// Hooks returns the commands of the `settings.hooks`.
+func (c Config) Hooks() entity.Hooks {
+	hooks := c.Settings.Hooks
+	if hooks == nil {
+		return entity.Hooks{}
+	}
+	return entity.Hooks{
+		BeforeAll: toCommands(hooks.BeforeAll),
+		AfterAll:  toCommands(hooks.AfterAll),
+		OnFailure: toCommands(hooks.OnFailure),
+	}
+}
+
+func (c Config) FilesActions(expander *Expander) ([]entity.Action[[]entity.UndefinedFile], error) {
+	return toActionsSlice(c.Files, func(file File) ([]entity.UndefinedFile, error) {
+		files, err := expander.files(file)
+		if err != nil {
+			return nil, newSourceError(file.Pos, err)
 		}
-		if get := file.Get; get != nil {
-			uFile.Get = &entity.HTTPClientParams{
-				URL:         get.URL,
-				Headers:     get.Headers,
-				QueryParams: get.QueryParams,
+		res := make([]entity.UndefinedFile, len(files))
+		for i, file := range files {
+			uFile := entity.UndefinedFile{
+				Path:     file.Path,
+				When:     file.When,
+				OnExists: file.OnExists,
+				Mode:     fs.FileMode(file.Mode),
+				Raw:      file.Template != nil && !*file.Template,
 			}
+			if file.Data != nil {
+				data := []byte(*file.Data)
+				uFile.Data = &data
+			}
+			if get := file.Get; get != nil {
+				uFile.Get = &entity.HTTPClientParams{
+					URL:         get.URL,
+					Headers:     get.Headers,
+					QueryParams: get.QueryParams,
+				}
+			}
+			if file.Local != nil {
+				uFile.Local = file.Local
+			}
+			res[i] = uFile
 		}
-		if file.Local != nil {
-			uFile.Local = file.Local
-		}
-		return uFile
+		return res, nil
 	})
 }
 
-func (c Config) DirActions() []entity.Action[[]string] {
-	return toActionsSlice(c.Dirs, func(dir string) string {
-		return dir
+func (c Config) DirActions(expander *Expander) ([]entity.Action[[]entity.Dir], error) {
+	return toActionsSlice(c.Dirs, func(dir Dir) ([]entity.Dir, error) {
+		dirs, err := expander.dirs(dir)
+		if err != nil {
+			return nil, newSourceError(dir.Pos, err)
+		}
+		res := make([]entity.Dir, len(dirs))
+		for i, dir := range dirs {
+			res[i] = entity.Dir{
+				Path: dir.Path,
+				When: dir.When,
+				Mode: fs.FileMode(dir.Mode),
+			}
+		}
+		return res, nil
 	})
 }
 
 func (c Config) RmActions() []entity.Action[[]string] {
-	return toActionsSlice(c.Rm, func(rms string) string {
-		return rms
+	// mapping never fails
+	actions, _ := toActionsSlice(c.Rm, func(rms string) ([]string, error) {
+		return []string{rms}, nil
+	})
+	return actions
+}
+
+func (c Config) FsActions() []entity.Action[[]entity.FsDir] {
+	// mapping never fails
+	actions, _ := toActionsSlice(c.FS, func(fss Fs) ([]entity.FsDir, error) {
+		return []entity.FsDir{{
+			Path:    fss.Path,
+			Include: fss.Include,
+			Exclude: fss.Exclude,
+		}}, nil
 	})
+	return actions
 }
 
-func (c Config) FsActions() []entity.Action[[]string] {
-	return toActionsSlice(c.FS, func(fss string) string {
-		return fss
+func (c Config) LinksActions(expander *Expander) ([]entity.Action[[]entity.Link], error) {
+	return toActionsSlice(c.Links, func(link Link) ([]entity.Link, error) {
+		links, err := expander.links(link)
+		if err != nil {
+			return nil, newSourceError(link.Pos, err)
+		}
+		res := make([]entity.Link, len(links))
+		for i, link := range links {
+			res[i] = entity.Link{
+				Path:     link.Path,
+				Target:   link.Target,
+				Hard:     link.Hard,
+				When:     link.When,
+				OnExists: link.OnExists,
+			}
+		}
+		return res, nil
 	})
 }
 
-func toActionsSlice[S any, T any](sections []Section[[]S], mapFn func(s S) T) []entity.Action[[]T] {
+func (c Config) CopyActions(expander *Expander) ([]entity.Action[[]entity.Copy], error) {
+	return toActionsSlice(c.Copy, func(cp Copy) ([]entity.Copy, error) {
+		copies, err := expander.copies(cp)
+		if err != nil {
+			return nil, newSourceError(cp.Pos, err)
+		}
+		res := make([]entity.Copy, len(copies))
+		for i, cp := range copies {
+			res[i] = entity.Copy{
+				Src:      cp.Src,
+				Dst:      cp.Dst,
+				Include:  cp.Include,
+				Exclude:  cp.Exclude,
+				Template: cp.Template == nil || *cp.Template,
+				When:     cp.When,
+			}
+		}
+		return res, nil
+	})
+}
+
+// toActionsSlice converts sections to actions, each section's value can be mapped to the multiple values (`foreach`).
+func toActionsSlice[S any, T any](sections []Section[[]S], mapFn func(s S) ([]T, error)) ([]entity.Action[[]T], error) {
 	actions := make([]entity.Action[[]T], len(sections))
 	for i, section := range sections {
 		action := entity.Action[[]T]{
-			Priority: section.Line,
-			Name:     section.Tag,
-			Val:      make([]T, len(section.Val)),
+			Priority:  section.Line,
+			Name:      section.Tag,
+			When:      section.When,
+			DependsOn: section.DependsOn,
+			Parallel:  section.Parallel,
+			Before:    toCommands(section.Before),
+			After:     toCommands(section.After),
+			Val:       make([]T, 0, len(section.Val)),
 		}
-		for j, val := range section.Val {
-			action.Val[j] = mapFn(val)
+		for _, val := range section.Val {
+			vals, err := mapFn(val)
+			if err != nil {
+				return nil, xerrors.Errorf("%s: %w", section.Tag, err)
+			}
+			action.Val = append(action.Val, vals...)
 		}
 		actions[i] = action
 	}
-	return actions
+	return actions, nil
+}
+
+func toCommands(commands []Command) []entity.Command {
+	if len(commands) == 0 {
+		return nil
+	}
+	res := make([]entity.Command, len(commands))
+	for i, cmd := range commands {
+		res[i] = entity.Command{
+			Cmd:  cmd.Exec,
+			Args: cmd.Args,
+			Dir:  cmd.Dir,
+			When: cmd.When,
+		}
+	}
+	return res
 }
 
 type Settings struct {
-	HTTP   *HTTPClient `yaml:"http"`
-	Groups Groups      `yaml:"groups"`
+	HTTP       *HTTPClient     `yaml:"http"`
+	Groups     Groups          `yaml:"groups"`
+	Strict     bool            `yaml:"strict"`
+	Namespaces []string        `yaml:"namespaces"`
+	Profiles   Profiles        `yaml:"profiles"`
+	Prompts    Prompts         `yaml:"prompts"`
+	Hooks      *Hooks          `yaml:"hooks"`
+	OnExists   entity.OnExists `yaml:"on_exists"`
+	Template   Template        `yaml:"template"`
+
+	DefaultFileMode FileMode `yaml:"default_file_mode"`
+	DefaultDirMode  FileMode `yaml:"default_dir_mode"`
+}
+
+// FileModes returns the default permissions of the created files and dirs.
+func (s Settings) FileModes() entity.FileModes {
+	return entity.FileModes{
+		File: fs.FileMode(s.DefaultFileMode),
+		Dir:  fs.FileMode(s.DefaultDirMode),
+	}
+}
+
+// Template contains the common templating settings.
+type Template struct {
+	// SkipExt is the list of the file extensions which are saved as is (without templating).
+	SkipExt []string `yaml:"skip_ext,flow"`
 }
 
 type HTTPClient struct {
@@ -141,17 +296,247 @@ type Group struct {
 	Manual  bool     `yaml:"manual"`
 }
 
+// Profile is the named overlay of the template variables and the actions selection.
+type Profile struct {
+	Name   string         `yaml:"name"`
+	Vars   map[string]any `yaml:"vars"`
+	Skip   []string       `yaml:"skip"`
+	Groups []string       `yaml:"groups"`
+}
+
+type Profiles []Profile
+
+// Select returns profiles in the order of the names.
+func (p Profiles) Select(names []string) (Profiles, error) {
+	selected := make(Profiles, 0, len(names))
+	for _, name := range names {
+		index := slices.IndexFunc(p, func(profile Profile) bool {
+			return profile.Name == name
+		})
+		if index < 0 {
+			return nil, xerrors.Errorf("profile [%s] not found", name)
+		}
+		selected = append(selected, p[index])
+	}
+	return selected, nil
+}
+
+// Vars returns template variables of the profiles merged in order.
+func (p Profiles) Vars() map[string]any {
+	vars := make(map[string]any)
+	for _, profile := range p {
+		vars = entity.MergeKeys(vars, profile.Vars)
+	}
+	return vars
+}
+
+func (p Profiles) Skip() []string {
+	var skip []string
+	for _, profile := range p {
+		skip = append(skip, profile.Skip...)
+	}
+	return skip
+}
+
+func (p Profiles) Groups() []string {
+	var groups []string
+	for _, profile := range p {
+		groups = append(groups, profile.Groups...)
+	}
+	return groups
+}
+
 type Section[T any] struct {
-	Line int
-	Tag  string
-	Val  T
+	Line      int
+	Column    int
+	Tag       string
+	When      string
+	DependsOn []string
+	Parallel  bool
+	Before    []Command
+	After     []Command
+	Val       T
+}
+
+// sectionObject is the object form of the section, which declares the section's options:
+//
+//	files_db:
+//	  when: .vars.db
+//	  depends_on: [dirs]
+//	  parallel: true
+//	  before: [echo start]
+//	  after: [echo done]
+//	  items:
+//	    - path: db.sql
+//	      data: some
+type sectionObject[T any] struct {
+	When      string    `yaml:"when"`
+	DependsOn []string  `yaml:"depends_on"`
+	Parallel  bool      `yaml:"parallel"`
+	Before    []Command `yaml:"before"`
+	After     []Command `yaml:"after"`
+	Items     T         `yaml:"items"`
+}
+
+func (s *Section[T]) valueType() reflect.Type {
+	return reflect.TypeOf(s.Val)
+}
+
+func (s *Section[T]) objectType() reflect.Type {
+	return reflect.TypeOf(sectionObject[T]{})
+}
+
+// decode decodes the section's value declared as the list or as the object form.
+func (s *Section[T]) decode(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return node.Decode(&s.Val)
+	}
+	var obj sectionObject[T]
+	if err := node.Decode(&obj); err != nil {
+		return err
+	}
+	s.When, s.DependsOn, s.Parallel, s.Val = obj.When, obj.DependsOn, obj.Parallel, obj.Items
+	s.Before, s.After = obj.Before, obj.After
+	return nil
 }
 
 type File struct {
-	Path  string  `yaml:"path"`
-	Data  *Bytes  `yaml:"data"`
-	Get   *Get    `yaml:"get"`
-	Local *string `yaml:"local"`
+	Path     string          `yaml:"path"`
+	Data     *Bytes          `yaml:"data"`
+	Get      *Get            `yaml:"get"`
+	Local    *string         `yaml:"local"`
+	When     string          `yaml:"when"`
+	Foreach  string          `yaml:"foreach"`
+	OnExists entity.OnExists `yaml:"on_exists"`
+	Mode     FileMode        `yaml:"mode"`
+	// Template is true by default.
+	Template *bool `yaml:"template"`
+
+	Pos Position `yaml:"-"`
+}
+
+func (f *File) UnmarshalYAML(node *yaml.Node) error {
+	type alias File
+	var file alias
+	if err := node.Decode(&file); err != nil {
+		return err
+	}
+	*f = (File)(file)
+	f.Pos = nodePosition(node)
+	return nil
+}
+
+// Dir is the directory entry declared as the path or as the object with the condition.
+type Dir struct {
+	Path    string   `yaml:"path"`
+	When    string   `yaml:"when"`
+	Foreach string   `yaml:"foreach"`
+	Mode    FileMode `yaml:"mode"`
+
+	Pos Position `yaml:"-"`
+}
+
+func (d *Dir) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		d.Pos = nodePosition(node)
+		return node.Decode(&d.Path)
+	}
+	type alias Dir
+	var dir alias
+	if err := node.Decode(&dir); err != nil {
+		return err
+	}
+	*d = (Dir)(dir)
+	d.Pos = nodePosition(node)
+	return nil
+}
+
+// Fs is the directory of the `fs` action declared as the path or as the object with the patterns.
+type Fs struct {
+	Path    string   `yaml:"path"`
+	Include []string `yaml:"include,flow"`
+	Exclude []string `yaml:"exclude,flow"`
+
+	Pos Position `yaml:"-"`
+}
+
+func (f *Fs) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		f.Pos = nodePosition(node)
+		return node.Decode(&f.Path)
+	}
+	type alias Fs
+	var fss alias
+	if err := node.Decode(&fss); err != nil {
+		return err
+	}
+	*f = (Fs)(fss)
+	f.Pos = nodePosition(node)
+	return nil
+}
+
+// Link is the symbolic link (or the hard link) entry.
+type Link struct {
+	Path     string          `yaml:"path"`
+	Target   string          `yaml:"target"`
+	Hard     bool            `yaml:"hard"`
+	When     string          `yaml:"when"`
+	Foreach  string          `yaml:"foreach"`
+	OnExists entity.OnExists `yaml:"on_exists"`
+
+	Pos Position `yaml:"-"`
+}
+
+func (l *Link) UnmarshalYAML(node *yaml.Node) error {
+	type alias Link
+	var link alias
+	if err := node.Decode(&link); err != nil {
+		return err
+	}
+	*l = (Link)(link)
+	l.Pos = nodePosition(node)
+	return nil
+}
+
+// Copy is the local directory tree, which is copied to the destination directory.
+type Copy struct {
+	Src     string   `yaml:"src"`
+	Dst     string   `yaml:"dst"`
+	Include []string `yaml:"include,flow"`
+	Exclude []string `yaml:"exclude,flow"`
+	// Template is true by default.
+	Template *bool  `yaml:"template"`
+	When     string `yaml:"when"`
+	Foreach  string `yaml:"foreach"`
+
+	Pos Position `yaml:"-"`
+}
+
+func (c *Copy) UnmarshalYAML(node *yaml.Node) error {
+	type alias Copy
+	var cp alias
+	if err := node.Decode(&cp); err != nil {
+		return err
+	}
+	*c = (Copy)(cp)
+	c.Pos = nodePosition(node)
+	return nil
+}
+
+// FileMode is the permissions declared as the octal number: `0755`, `"0644"`, `0o600`.
+type FileMode fs.FileMode
+
+func (m *FileMode) UnmarshalYAML(node *yaml.Node) error {
+	value := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(node.Value)), "0o")
+	mode, err := strconv.ParseUint(value, 8, 32)
+	if err != nil {
+		return xerrors.Errorf("mode [%s] is not an octal number", node.Value)
+	}
+	if fs.FileMode(mode) > fs.ModePerm {
+		return xerrors.Errorf("mode [%s] is not a permission (max %#o)", node.Value, fs.ModePerm)
+	}
+	*m = FileMode(mode)
+	return nil
 }
 
 type Bytes []byte
@@ -162,26 +547,32 @@ func (fd *Bytes) UnmarshalYAML(node *yaml.Node) error {
 }
 
 type Command struct {
-	Dir  string   `yaml:"dir"`
-	Exec string   `yaml:"exec"`
-	Args []string `yaml:"args,flow"`
+	Dir     string   `yaml:"dir"`
+	Exec    string   `yaml:"exec"`
+	Args    []string `yaml:"args,flow"`
+	When    string   `yaml:"when"`
+	Foreach string   `yaml:"foreach"`
+
+	Pos Position `yaml:"-"`
 }
 
-func (c *Command) UnmarshalYAML(unmarshal func(any) error) error {
+func (c *Command) UnmarshalYAML(node *yaml.Node) error {
 	var raw string
-	if err := unmarshal(&raw); err == nil {
+	if err := node.Decode(&raw); err == nil {
 		*c, err = commandFromString(raw)
 		if err != nil {
-			return err
+			return newSourceError(nodePosition(node), err)
 		}
+		c.Pos = nodePosition(node)
 		return nil
 	}
 	type alias Command
 	var cmd alias
-	if err := unmarshal(&cmd); err != nil {
+	if err := node.Decode(&cmd); err != nil {
 		return err
 	}
 	*c = (Command)(cmd)
+	c.Pos = nodePosition(node)
 	return nil
 }
 
@@ -220,23 +611,88 @@ func (addr *AddrURL) UnmarshalYAML(unmarshal func(any) error) error {
 }
 
 func (c Config) Validate() error {
+	if errs := c.ValidateAll(); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// ValidateAll returns all problems of the config instead of the first one.
+func (c Config) ValidateAll() []error {
+	var errs []error
 	for i, files := range c.Files {
 		for _, file := range files.Val {
 			err := validateFile(file)
 			if err != nil {
-				return xerrors.Errorf("files: %d [%s]: %w", i, file.Path, err)
+				errs = append(errs, xerrors.Errorf("files: %d [%s]: %w", i, file.Path, newSourceError(file.Pos, err)))
+			}
+		}
+	}
+
+	for i, links := range c.Links {
+		for _, link := range links.Val {
+			err := validateLink(link)
+			if err != nil {
+				errs = append(errs, xerrors.Errorf("links: %d [%s]: %w", i, link.Path, newSourceError(link.Pos, err)))
+			}
+		}
+	}
+
+	for i, fss := range c.FS {
+		for _, dir := range fss.Val {
+			if err := validatePatterns(dir.Include, dir.Exclude); err != nil {
+				errs = append(errs, xerrors.Errorf("fs: %d [%s]: %w", i, dir.Path, newSourceError(dir.Pos, err)))
+			}
+		}
+	}
+
+	for i, copies := range c.Copy {
+		for _, cp := range copies.Val {
+			err := validateCopy(cp)
+			if err != nil {
+				errs = append(errs, xerrors.Errorf("copy: %d [%s]: %w", i, cp.Src, newSourceError(cp.Pos, err)))
 			}
 		}
 	}
 
 	if err := validateGroups(c.Settings.Groups); err != nil {
-		return xerrors.Errorf("groups: %w", err)
+		errs = append(errs, xerrors.Errorf("groups: %w", err))
+	}
+
+	if err := validateProfiles(c.Settings.Profiles); err != nil {
+		errs = append(errs, xerrors.Errorf("profiles: %w", err))
+	}
+
+	if err := c.Settings.OnExists.Valid(); err != nil {
+		errs = append(errs, xerrors.Errorf("settings: %w", err))
+	}
+
+	hooks := slices.Concat(
+		c.Settings.Hooks.commands(),
+		sectionsHooks(c.Dirs),
+		sectionsHooks(c.Rm),
+		sectionsHooks(c.Files),
+		sectionsHooks(c.Cmd),
+		sectionsHooks(c.FS),
+		sectionsHooks(c.Links),
+		sectionsHooks(c.Copy),
+	)
+	for _, cmd := range hooks {
+		if cmd.Foreach != entity.Empty {
+			errs = append(errs, xerrors.Errorf("hooks: %w", newSourceError(cmd.Pos, xerrors.Errorf("`foreach` is not supported"))))
+		}
+	}
+
+	for _, prompt := range c.Settings.Prompts {
+		if err := prompt.validate(); err != nil {
+			errs = append(errs, xerrors.Errorf("prompts: %w", newSourceError(prompt.Pos, err)))
+		}
 	}
 
 	if err := validateConfigSections(c); err != nil {
-		return xerrors.Errorf("sections: %w", err)
+		errs = append(errs, xerrors.Errorf("sections: %w", err))
 	}
-	return nil
+	return errs
 }
 
 func validateFile(file File) error {
@@ -250,6 +706,50 @@ func validateFile(file File) error {
 		return xerrors.Errorf("files: save `path` are empty")
 	}
 
+	if err := file.OnExists.Valid(); err != nil {
+		return xerrors.Errorf("files: %w", err)
+	}
+	return nil
+}
+
+func validateLink(link Link) error {
+	switch {
+	case strings.TrimSpace(link.Path) == entity.Empty:
+		return xerrors.Errorf("links: `path` is empty")
+	case strings.TrimSpace(link.Target) == entity.Empty:
+		return xerrors.Errorf("links: `target` is empty")
+	}
+
+	if err := link.OnExists.Valid(); err != nil {
+		return xerrors.Errorf("links: %w", err)
+	}
+	switch link.OnExists {
+	case entity.OnExistsAppend, entity.OnExistsPrepend:
+		return xerrors.Errorf("links: `on_exists` [%s] is not supported", link.OnExists)
+	}
+	return nil
+}
+
+func validateCopy(cp Copy) error {
+	switch {
+	case strings.TrimSpace(cp.Src) == entity.Empty:
+		return xerrors.Errorf("copy: `src` is empty")
+	case strings.TrimSpace(cp.Dst) == entity.Empty:
+		return xerrors.Errorf("copy: `dst` is empty")
+	}
+
+	if err := validatePatterns(cp.Include, cp.Exclude); err != nil {
+		return xerrors.Errorf("copy: %w", err)
+	}
+	return nil
+}
+
+func validatePatterns(include, exclude []string) error {
+	for _, pattern := range slices.Concat(include, exclude) {
+		if !doublestar.ValidatePattern(pattern) {
+			return xerrors.Errorf("invalid pattern [%s]", pattern)
+		}
+	}
 	return nil
 }
 
@@ -275,6 +775,20 @@ func validateGroups(groups Groups) error {
 	return nil
 }
 
+func validateProfiles(profiles Profiles) error {
+	names := make(map[string]struct{}, len(profiles))
+	for _, profile := range profiles {
+		if strings.TrimSpace(profile.Name) == entity.Empty {
+			return xerrors.Errorf("profile `name` is empty")
+		}
+		if _, ok := names[profile.Name]; ok {
+			return xerrors.Errorf("duplicate name [%s]", profile.Name)
+		}
+		names[profile.Name] = struct{}{}
+	}
+	return nil
+}
+
 func validateConfigSections(conf Config) error {
 	var (
 		files = len(conf.Files)
@@ -282,11 +796,13 @@ func validateConfigSections(conf Config) error {
 		rm    = len(conf.Rm)
 		cmd   = len(conf.Cmd)
 		fs    = len(conf.FS)
+		links = len(conf.Links)
+		cp    = len(conf.Copy)
 	)
-	if files == 0 && dirs == 0 && rm == 0 && cmd == 0 && fs == 0 {
+	if files == 0 && dirs == 0 && rm == 0 && cmd == 0 && fs == 0 && links == 0 && cp == 0 {
 		return xerrors.Errorf(
-			"config not contains executable actions [dirs: %d, rm: %d, files: %d, cms: %d, fs: %d]",
-			dirs, rm, files, cmd, fs,
+			"config not contains executable actions [dirs: %d, rm: %d, files: %d, cms: %d, fs: %d, links: %d, copy: %d]",
+			dirs, rm, files, cmd, fs, links, cp,
 		)
 	}
 	return nil
@@ -3,6 +3,7 @@ package config
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"regexp"
 	"testing"
 
@@ -35,7 +36,7 @@ steps:
 `
 		)
 
-		rawConf, mapConf, err := NewRawPreprocessor(name, nil, nil, nil).Process([]byte(in))
+		rawConf, mapConf, err := NewRawPreprocessor(name, NewIncludeResolver(entity.ConfigFormatYAML, os.ReadFile), nil, nil, nil, nil, nil).Process([]byte(in))
 		assert.NoError(t, err)
 		assert.Equal(t, expected, string(rawConf))
 		assert.NotEmpty(t, mapConf)
@@ -52,7 +53,7 @@ steps:
 `
 		)
 
-		res, _, err := NewRawPreprocessor(name, nil, entity.TemplateFnsMap, nil).Process([]byte(in))
+		res, _, err := NewRawPreprocessor(name, NewIncludeResolver(entity.ConfigFormatYAML, os.ReadFile), nil, entity.TemplateFnsMap, nil, nil, nil).Process([]byte(in))
 		assert.NoError(t, err)
 		assert.Regexp(t, regexp.MustCompile(exp), string(res))
 	})
@@ -70,8 +71,11 @@ steps:
 
 		res, _, err := NewRawPreprocessor(
 			name,
+			NewIncludeResolver(entity.ConfigFormatYAML, os.ReadFile),
 			map[string]any{"vars": map[string]any{"service_name": "SOME"}},
 			nil,
+			nil,
+			nil,
 			nil).
 			Process([]byte(in))
 		assert.NoError(t, err)
@@ -89,7 +93,7 @@ steps:
 `
 		)
 
-		res, _, err := NewRawPreprocessor(name, nil, nil, nil).Process([]byte(in))
+		res, _, err := NewRawPreprocessor(name, NewIncludeResolver(entity.ConfigFormatYAML, os.ReadFile), nil, nil, nil, nil, nil).Process([]byte(in))
 		assert.NoError(t, err)
 		assert.Equal(t, expected, string(res))
 	})
@@ -102,8 +106,83 @@ steps:
 		)
 
 		options := []string{fmt.Sprintf("%v=%v", entity.TemplateOptionsMissingKey, entity.MissingKeyError)}
-		_, _, err := NewRawPreprocessor(name, nil, nil, options).Process([]byte(in))
+		_, _, err := NewRawPreprocessor(name, NewIncludeResolver(entity.ConfigFormatYAML, os.ReadFile), nil, nil, options, nil, nil).Process([]byte(in))
+		assert.Error(t, err)
+	})
+}
+
+func Test_RawPreprocessor_profiles(t *testing.T) {
+	t.Parallel()
+
+	const (
+		name = "conf"
+		in   = `
+settings:
+  profiles:
+    - name: stage
+      vars:
+        vars:
+          env: stage
+          replicas: 2
+    - name: prod
+      vars:
+        vars:
+          env: prod
+vars:
+  env: dev
+  replicas: 1
+  port: 80
+dirs:
+  - "{{ .vars.env }}/{{ .vars.replicas }}/{{ .vars.port }}/{{ range .progen.profiles }}{{ . }}.{{ end }}"
+`
+	)
+
+	newPreprocessor := func(templateVars map[string]any, profiles ...string) *RawPreprocessor {
+		return NewRawPreprocessor(name, NewIncludeResolver(entity.ConfigFormatYAML, os.ReadFile), templateVars, nil, nil, profiles, nil)
+	}
+
+	t.Run("success_without_profiles", func(t *testing.T) {
+		res, _, err := newPreprocessor(nil).Process([]byte(in))
+		assert.NoError(t, err)
+		assert.Contains(t, string(res), `- "dev/1/80/"`)
+	})
+	t.Run("success_apply_profiles_in_order", func(t *testing.T) {
+		res, _, err := newPreprocessor(nil, "stage", "prod").Process([]byte(in))
+		assert.NoError(t, err)
+		assert.Contains(t, string(res), `- "prod/2/80/stage.prod."`)
+	})
+	t.Run("success_flags_vars_override_profiles", func(t *testing.T) {
+		vars := map[string]any{"vars": map[string]any{"env": "local"}}
+		res, _, err := newPreprocessor(vars, "prod").Process([]byte(in))
+		assert.NoError(t, err)
+		assert.Contains(t, string(res), `- "local/1/80/prod."`)
+	})
+	t.Run("error_when_profile_not_found", func(t *testing.T) {
+		_, _, err := newPreprocessor(nil, "test").Process([]byte(in))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "profile [test] not found")
+	})
+}
+
+func Test_Profiles(t *testing.T) {
+	t.Parallel()
+
+	profiles := Profiles{
+		{Name: "a", Skip: []string{"cmd"}, Groups: []string{"g1"}},
+		{Name: "b", Skip: []string{"rm"}, Groups: []string{"g2"}},
+	}
+
+	t.Run("success_select", func(t *testing.T) {
+		selected, err := profiles.Select([]string{"b", "a"})
+		assert.NoError(t, err)
+		assert.Equal(t, Profiles{profiles[1], profiles[0]}, selected)
+		assert.Equal(t, []string{"rm", "cmd"}, selected.Skip())
+		assert.Equal(t, []string{"g2", "g1"}, selected.Groups())
+	})
+	t.Run("error_validate_duplicate_name", func(t *testing.T) {
+		err := validateProfiles(append(profiles, Profile{Name: "a"}))
 		assert.Error(t, err)
+		assert.Equal(t, "duplicate name [a]", err.Error())
 	})
 }
 
@@ -161,6 +240,71 @@ func Test_validateFile(t *testing.T) {
 		err = validateFile(in)
 		assert.Error(t, err)
 	})
+	t.Run("error_when_on_exists_is_unknown", func(t *testing.T) {
+		in := File{
+			Path:     path,
+			Get:      &Get{},
+			OnExists: entity.OnExists("unknown"),
+		}
+		err := validateFile(in)
+		assert.Error(t, err)
+
+		in.OnExists = entity.OnExistsBackup
+		err = validateFile(in)
+		assert.NoError(t, err)
+	})
+}
+
+func Test_validateLink(t *testing.T) {
+	t.Parallel()
+
+	const (
+		path   = "some_path"
+		target = "some_target"
+	)
+	t.Run("not_error", func(t *testing.T) {
+		err := validateLink(Link{Path: path, Target: target, OnExists: entity.OnExistsBackup})
+		assert.NoError(t, err)
+	})
+	t.Run("error_when_path_is_empty", func(t *testing.T) {
+		err := validateLink(Link{Path: "  ", Target: target})
+		assert.Error(t, err)
+	})
+	t.Run("error_when_target_is_empty", func(t *testing.T) {
+		err := validateLink(Link{Path: path})
+		assert.Error(t, err)
+	})
+	t.Run("error_when_on_exists_is_not_supported", func(t *testing.T) {
+		err := validateLink(Link{Path: path, Target: target, OnExists: entity.OnExistsAppend})
+		assert.Error(t, err)
+
+		err = validateLink(Link{Path: path, Target: target, OnExists: entity.OnExists("unknown")})
+		assert.Error(t, err)
+	})
+}
+
+func Test_validateCopy(t *testing.T) {
+	t.Parallel()
+
+	const (
+		src = "some_src"
+		dst = "some_dst"
+	)
+	t.Run("not_error", func(t *testing.T) {
+		err := validateCopy(Copy{Src: src, Dst: dst, Include: []string{"**/*.go"}, Exclude: []string{"vendor"}})
+		assert.NoError(t, err)
+	})
+	t.Run("error_when_src_or_dst_is_empty", func(t *testing.T) {
+		err := validateCopy(Copy{Dst: dst})
+		assert.Error(t, err)
+
+		err = validateCopy(Copy{Src: src, Dst: " "})
+		assert.Error(t, err)
+	})
+	t.Run("error_when_pattern_is_invalid", func(t *testing.T) {
+		err := validateCopy(Copy{Src: src, Dst: dst, Exclude: []string{"[a"}})
+		assert.Error(t, err)
+	})
 }
 
 func Test_validateGroups(t *testing.T) {
@@ -195,6 +339,69 @@ func Test_validateGroups(t *testing.T) {
 	})
 }
 
+func Test_Config_ValidateAll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		data := Bytes("data")
+		conf := Config{
+			Files: []Section[[]File]{{Val: []File{{Path: "a", Data: &data}}}},
+		}
+		assert.Empty(t, conf.ValidateAll())
+		assert.NoError(t, conf.Validate())
+	})
+	t.Run("error_return_all_problems", func(t *testing.T) {
+		conf := Config{
+			Settings: Settings{Groups: Groups{{Name: "a"}, {Name: "a"}}},
+			Files: []Section[[]File]{
+				{Val: []File{{Path: "a"}, {Path: "b"}}},
+			},
+		}
+		errs := conf.ValidateAll()
+		assert.Len(t, errs, 3)
+		assert.Contains(t, errs[0].Error(), "files: 0 [a]")
+		assert.Contains(t, errs[1].Error(), "files: 0 [b]")
+		assert.Contains(t, errs[2].Error(), "groups: duplicate names [a]")
+		assert.EqualError(t, conf.Validate(), errs[0].Error())
+	})
+	t.Run("error_locate_dir_and_fs_entries", func(t *testing.T) {
+		const in = `
+dirs:
+  - api
+  - path: "{{ .vars.missing }}"
+    foreach: .vars.missing
+fs:
+  - web
+  - path: src
+    include: ["["]
+`
+		var raw struct {
+			Dirs []Dir `yaml:"dirs"`
+			Fs   []Fs  `yaml:"fs"`
+		}
+		assert.NoError(t, yaml.Unmarshal([]byte(in), &raw))
+		assert.Equal(t, Position{Line: 3, Column: 5}, raw.Dirs[0].Pos)
+		assert.Equal(t, Position{Line: 4, Column: 5}, raw.Dirs[1].Pos)
+		assert.Equal(t, Position{Line: 7, Column: 5}, raw.Fs[0].Pos)
+		assert.Equal(t, Position{Line: 8, Column: 5}, raw.Fs[1].Pos)
+
+		conf := Config{
+			Dirs: []Section[[]Dir]{{Tag: TagDirs, Val: raw.Dirs}},
+			FS:   []Section[[]Fs]{{Tag: TagFS, Val: raw.Fs}},
+		}
+		var srcErr *SourceError
+
+		errs := conf.ValidateAll()
+		assert.Len(t, errs, 1)
+		assert.ErrorAs(t, errs[0], &srcErr)
+		assert.Equal(t, Position{Line: 8, Column: 5}, srcErr.Position)
+
+		_, err := conf.DirActions(NewExpander(map[string]any{"vars": map[string]any{}}, entity.TemplateFnsMap, nil))
+		assert.ErrorAs(t, err, &srcErr)
+		assert.Equal(t, Position{Line: 4, Column: 5}, srcErr.Position)
+	})
+}
+
 func Test_Read(t *testing.T) {
 	t.Parallel()
 
@@ -268,3 +475,64 @@ func Test_AddrURL_UnmarshalYAML(t *testing.T) {
 		a.Error(err)
 	})
 }
+
+func Test_Config_FilesActions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success_convert_file_data", func(t *testing.T) {
+		var (
+			data     = Bytes("some data")
+			expected = []byte("some data")
+			local    = "local_path"
+			conf     = Config{
+				Files: []Section[[]File]{
+					{
+						Line: 1,
+						Tag:  TagFiles,
+						Val: []File{
+							{Path: "a.txt", Data: &data},
+							{Path: "b.txt", Local: &local},
+						},
+					},
+				},
+			}
+		)
+
+		actions, err := conf.FilesActions(nil)
+		assert.NoError(t, err)
+		assert.Len(t, actions, 1)
+		assert.Equal(t, []entity.UndefinedFile{
+			{Path: "a.txt", Data: &expected},
+			{Path: "b.txt", Local: &local},
+		}, actions[0].Val)
+	})
+}
+
+func Test_FileMode_UnmarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	type testMode struct {
+		Mode FileMode `yaml:"mode"`
+	}
+
+	t.Run("success", func(t *testing.T) {
+		for in, exp := range map[string]FileMode{
+			`mode: 0755`:   0o755,
+			`mode: "0644"`: 0o644,
+			`mode: 0o600`:  0o600,
+			`mode: 700`:    0o700,
+		} {
+			var res testMode
+			err := yaml.Unmarshal([]byte(in), &res)
+			assert.NoError(t, err, in)
+			assert.Equal(t, exp, res.Mode, in)
+		}
+	})
+	t.Run("error_when_mode_is_invalid", func(t *testing.T) {
+		for _, in := range []string{`mode: 0789`, `mode: rwx`, `mode: 01777`} {
+			var res testMode
+			err := yaml.Unmarshal([]byte(in), &res)
+			assert.Error(t, err, in)
+		}
+	})
+}
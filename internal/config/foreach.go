@@ -0,0 +1,325 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+// Template data keys of the `foreach` entries.
+const (
+	ForeachItem  = "item"
+	ForeachKey   = "key"
+	ForeachIndex = "index"
+
+	tagForeach = "foreach"
+)
+
+var (
+	templateActionRegexp = regexp.MustCompile(`(?s)\{\{.*?\}\}`)
+	loopVarRegexp        = regexp.MustCompile(`(?:^|[\s(])\.(?:` + ForeachItem + `|` + ForeachKey + `|` + ForeachIndex + `)\b`)
+)
+
+// Expander expands `foreach` entries of the actions: the entry is repeated for each element of the list or the map
+// variable and the entry's templates are processed with `.item`, `.key`, `.index` of the element.
+type Expander struct {
+	templateData    map[string]any
+	templateFns     map[string]any
+	templateOptions []string
+}
+
+func NewExpander(templateData, templateFns map[string]any, templateOptions []string) *Expander {
+	return &Expander{
+		templateData:    templateData,
+		templateFns:     templateFns,
+		templateOptions: templateOptions,
+	}
+}
+
+type (
+	foreachElement struct {
+		key  any
+		item any
+	}
+
+	// loopRenderer processes the entry's templates with the data of the `foreach` element.
+	loopRenderer struct {
+		name string
+		proc *entity.TmplProc
+	}
+)
+
+func (r loopRenderer) text(s string) (string, error) {
+	return r.proc.Process(r.name, s)
+}
+
+func (r loopRenderer) texts(values []string) ([]string, error) {
+	res := make([]string, len(values))
+	for i, val := range values {
+		var err error
+		if res[i], err = r.text(val); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// when evaluates the `when` expression with the element's data and returns the evaluated expression.
+func (r loopRenderer) when(expr string) (string, error) {
+	if strings.TrimSpace(expr) == entity.Empty {
+		return expr, nil
+	}
+	return r.text("{{ if " + expr + " }}" + strconv.FormatBool(true) + "{{ else }}" + strconv.FormatBool(false) + "{{ end }}")
+}
+
+func expand[T any](e *Expander, foreach string, entry T, renderFn func(entry T, r loopRenderer) (T, error)) ([]T, error) {
+	if e == nil || strings.TrimSpace(foreach) == entity.Empty {
+		return []T{entry}, nil
+	}
+
+	val, ok := entity.LookupVar(e.templateData, foreach)
+	if !ok {
+		return nil, xerrors.Errorf("foreach [%s]: variable not found", foreach)
+	}
+	elements, err := foreachElements(val)
+	if err != nil {
+		return nil, xerrors.Errorf("foreach [%s]: %w", foreach, err)
+	}
+
+	res := make([]T, 0, len(elements))
+	for i, element := range elements {
+		data := make(map[string]any, len(e.templateData)+3)
+		for key, v := range e.templateData {
+			data[key] = v
+		}
+		data[ForeachItem], data[ForeachKey], data[ForeachIndex] = element.item, element.key, i
+
+		expanded, err := renderFn(entry, loopRenderer{
+			name: foreach,
+			proc: entity.NewTemplateProc(data, e.templateFns, e.templateOptions),
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("foreach [%s]: element [%v]: %w", foreach, element.key, err)
+		}
+		res = append(res, expanded)
+	}
+	return res, nil
+}
+
+// foreachElements returns elements of the list (key is the index) or the map (ordered by keys).
+func foreachElements(val any) ([]foreachElement, error) {
+	v := reflect.ValueOf(val)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		elements := make([]foreachElement, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			elements[i] = foreachElement{key: i, item: v.Index(i).Interface()}
+		}
+		return elements, nil
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		elements := make([]foreachElement, len(keys))
+		for i, key := range keys {
+			elements[i] = foreachElement{key: key.Interface(), item: v.MapIndex(key).Interface()}
+		}
+		return elements, nil
+	default:
+		return nil, xerrors.Errorf("value must be a list or a map, got [%T]", val)
+	}
+}
+
+func (e *Expander) files(file File) ([]File, error) {
+	return expand(e, file.Foreach, file, func(file File, r loopRenderer) (File, error) {
+		var err error
+		if file.Path, err = r.text(file.Path); err != nil {
+			return file, err
+		}
+		if file.When, err = r.when(file.When); err != nil {
+			return file, err
+		}
+		if file.Data != nil {
+			data, err := r.text(string(*file.Data))
+			if err != nil {
+				return file, err
+			}
+			bytes := Bytes(data)
+			file.Data = &bytes
+		}
+		if file.Local != nil {
+			local, err := r.text(*file.Local)
+			if err != nil {
+				return file, err
+			}
+			file.Local = &local
+		}
+		if file.Get != nil {
+			get := *file.Get
+			if get.URL, err = r.text(get.URL); err != nil {
+				return file, err
+			}
+			file.Get = &get
+		}
+		return file, nil
+	})
+}
+
+func (e *Expander) commands(cmd Command) ([]Command, error) {
+	return expand(e, cmd.Foreach, cmd, func(cmd Command, r loopRenderer) (Command, error) {
+		var err error
+		if cmd.Exec, err = r.text(cmd.Exec); err != nil {
+			return cmd, err
+		}
+		if cmd.Dir, err = r.text(cmd.Dir); err != nil {
+			return cmd, err
+		}
+		if cmd.Args, err = r.texts(cmd.Args); err != nil {
+			return cmd, err
+		}
+		cmd.When, err = r.when(cmd.When)
+		return cmd, err
+	})
+}
+
+func (e *Expander) dirs(dir Dir) ([]Dir, error) {
+	return expand(e, dir.Foreach, dir, func(dir Dir, r loopRenderer) (Dir, error) {
+		var err error
+		if dir.Path, err = r.text(dir.Path); err != nil {
+			return dir, err
+		}
+		dir.When, err = r.when(dir.When)
+		return dir, err
+	})
+}
+
+func (e *Expander) links(link Link) ([]Link, error) {
+	return expand(e, link.Foreach, link, func(link Link, r loopRenderer) (Link, error) {
+		var err error
+		if link.Path, err = r.text(link.Path); err != nil {
+			return link, err
+		}
+		if link.Target, err = r.text(link.Target); err != nil {
+			return link, err
+		}
+		link.When, err = r.when(link.When)
+		return link, err
+	})
+}
+
+func (e *Expander) copies(cp Copy) ([]Copy, error) {
+	return expand(e, cp.Foreach, cp, func(cp Copy, r loopRenderer) (Copy, error) {
+		var err error
+		if cp.Src, err = r.text(cp.Src); err != nil {
+			return cp, err
+		}
+		if cp.Dst, err = r.text(cp.Dst); err != nil {
+			return cp, err
+		}
+		if cp.Include, err = r.texts(cp.Include); err != nil {
+			return cp, err
+		}
+		if cp.Exclude, err = r.texts(cp.Exclude); err != nil {
+			return cp, err
+		}
+		cp.When, err = r.when(cp.When)
+		return cp, err
+	})
+}
+
+// protectLoopVars escapes the template actions, which use `.item`, `.key`, `.index` in the `foreach` entries
+// of the raw config, so the actions are processed only when the entries are expanded.
+func protectLoopVars(data []byte) []byte {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return data
+	}
+	root := documentRoot(&doc)
+	if root == nil {
+		return data
+	}
+
+	lines := strings.SplitAfter(string(data), entity.NewLine)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if !IsActionTag(root.Content[i].Value) {
+			continue
+		}
+		end := len(lines)
+		if i+2 < len(root.Content) {
+			end = root.Content[i+2].Line - 1
+		}
+
+		entries := root.Content[i+1]
+		if index := mappingIndex(entries, "items"); entries.Kind == yaml.MappingNode && index >= 0 {
+			entries = entries.Content[index+1]
+		}
+		if entries.Kind != yaml.SequenceNode {
+			continue
+		}
+		for j, entry := range entries.Content {
+			if entry.Kind != yaml.MappingNode || mappingIndex(entry, tagForeach) < 0 {
+				continue
+			}
+			to := end
+			if j+1 < len(entries.Content) {
+				to = entries.Content[j+1].Line - 1
+			}
+			protectActions(lines, entry.Line, to, loopVarRegexp)
+		}
+	}
+	return []byte(strings.Join(lines, entity.Empty))
+}
+
+// protectActions escapes the template actions of the lines (from 1) which match the regexp.
+// The actions can be multi-line, so the lines are processed as the one block.
+func protectActions(lines []string, from, to int, re *regexp.Regexp) {
+	to = min(to, len(lines))
+	if from < 1 || from > to {
+		return
+	}
+	// depth of the escaped control structures (`range`, `if`, ...), all actions of them are escaped
+	depth := 0
+	block := strings.Join(lines[from-1:to], entity.Empty)
+	block = templateActionRegexp.ReplaceAllStringFunc(block, func(action string) string {
+		var (
+			inner   = strings.TrimSpace(strings.Trim(action, "{}-"))
+			keyword string
+		)
+		if fields := strings.Fields(inner); len(fields) > 0 {
+			keyword = fields[0]
+		}
+		if depth == 0 && !re.MatchString(inner) {
+			return action
+		}
+		switch keyword {
+		case "if", "range", "with", "block", "define":
+			depth++
+		case "end":
+			depth--
+		}
+		return "{{ " + quoteAction(action) + " }}"
+	})
+	// the block keeps the line breaks, so the lines of the block are joined into the first one
+	lines[from-1] = block
+	for i := from; i < to; i++ {
+		lines[i] = entity.Empty
+	}
+}
+
+// quoteAction returns the action as the raw template string, which keeps the lines of the config (the positions
+// of the errors) and the quoted YAML scalars valid for the next protection. The action with the backtick is quoted.
+func quoteAction(action string) string {
+	if !strings.Contains(action, "`") {
+		return "`" + action + "`"
+	}
+	return strconv.Quote(action)
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+func Test_protectLoopVars(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success_protect_loop_vars", func(t *testing.T) {
+		const (
+			in = `vars:
+  item: root
+dirs:
+  - "{{ .item }}"
+  - path: "{{ .vars.name }}/{{ .item }}"
+    foreach: .vars.list
+files:
+  items:
+    - path: "{{.key}}.go"
+      foreach: .vars.map
+      data: |
+        {{ .vars.name }} {{ printf "%s" .item.name }} {{- .index -}}
+        {{ range .item.fields }}{{ . }}{{ end }} {{ .vars.name }}
+    - path: "{{ .item }}"
+`
+			expected = `vars:
+  item: root
+dirs:
+  - "{{ .item }}"
+  - path: "{{ .vars.name }}/{{ ` + "`" + `{{ .item }}` + "`" + ` }}"
+    foreach: .vars.list
+files:
+  items:
+    - path: "{{ ` + "`" + `{{.key}}` + "`" + ` }}.go"
+      foreach: .vars.map
+      data: |
+        {{ .vars.name }} {{ ` + "`" + `{{ printf "%s" .item.name }}` + "`" + ` }} {{ ` + "`" + `{{- .index -}}` + "`" + ` }}
+        {{ ` + "`" + `{{ range .item.fields }}` + "`" + ` }}{{ ` + "`" + `{{ . }}` + "`" + ` }}{{ ` + "`" + `{{ end }}` + "`" + ` }} {{ .vars.name }}
+    - path: "{{ .item }}"
+`
+		)
+		assert.Equal(t, expected, string(protectLoopVars([]byte(in))))
+	})
+	t.Run("success_protect_multi_line_actions", func(t *testing.T) {
+		const (
+			in = `files:
+  - path: "{{ .item }}.txt"
+    foreach: .vars.list
+    data: |
+      {{- range
+        .item.fields }}{{ . }}{{ end }}
+      {{ .vars.name }}
+cmd:
+  - echo {{ .item }}
+`
+			expected = `files:
+  - path: "{{ ` + "`" + `{{ .item }}` + "`" + ` }}.txt"
+    foreach: .vars.list
+    data: |
+      {{ ` + "`" + `{{- range
+        .item.fields }}` + "`" + ` }}{{ ` + "`" + `{{ . }}` + "`" + ` }}{{ ` + "`" + `{{ end }}` + "`" + ` }}
+      {{ .vars.name }}
+cmd:
+  - echo {{ .item }}
+`
+		)
+		assert.Equal(t, expected, string(protectLoopVars([]byte(in))))
+	})
+}
+
+func Test_Config_foreach(t *testing.T) {
+	t.Parallel()
+
+	const (
+		name = "progen.yml"
+		in   = `
+vars:
+  name: svc
+  entities: [user, order]
+  services:
+    api: 8080
+    worker: 9090
+dirs:
+  - path: internal/{{ .item }}
+    foreach: .vars.entities
+files:
+  - path: internal/{{ .item }}/handler.go
+    foreach: .vars.entities
+    data: |
+      package {{ .item }} // {{ .vars.name }} {{ .index }}
+cmd:
+  - exec: echo
+    args: [ "{{ .key }}={{ .item }}" ]
+    foreach: .vars.services
+    when: ne .key "worker"
+`
+	)
+
+	rawConfig, templateData, err := NewRawPreprocessor(name, NewIncludeResolver(entity.ConfigFormatYAML, os.ReadFile), nil, nil, nil, nil, nil).
+		Process([]byte(in))
+	assert.NoError(t, err)
+
+	conf, err := NewYamlConfigUnmarshaler(true).Unmarshal(rawConfig)
+	assert.NoError(t, err)
+
+	expander := NewExpander(templateData, entity.TemplateFnsMap, nil)
+
+	t.Run("success_expand_dirs", func(t *testing.T) {
+		dirs, err := conf.DirActions(expander)
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.Dir{{Path: "internal/user"}, {Path: "internal/order"}}, dirs[0].Val)
+	})
+	t.Run("success_expand_files", func(t *testing.T) {
+		files, err := conf.FilesActions(expander)
+		assert.NoError(t, err)
+		assert.Len(t, files[0].Val, 2)
+		assert.Equal(t, "internal/order/handler.go", files[0].Val[1].Path)
+		assert.Equal(t, "package order // svc 1\n", string(*files[0].Val[1].Data))
+	})
+	t.Run("success_expand_map_commands", func(t *testing.T) {
+		cmds, err := conf.CommandActions(expander)
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.Command{
+			{Cmd: "echo", Args: []string{"api=8080"}, When: "true"},
+			{Cmd: "echo", Args: []string{"worker=9090"}, When: "false"},
+		}, cmds[0].Val)
+	})
+	t.Run("error_when_foreach_is_not_list", func(t *testing.T) {
+		conf := Config{Dirs: []Section[[]Dir]{{Tag: TagDirs, Val: []Dir{{Path: "a", Foreach: ".vars.name"}}}}}
+		_, err := conf.DirActions(expander)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "foreach [.vars.name]: value must be a list or a map")
+	})
+	t.Run("error_when_foreach_not_found", func(t *testing.T) {
+		conf := Config{Dirs: []Section[[]Dir]{{Tag: TagDirs, Val: []Dir{{Path: "a", Foreach: ".vars.missing"}}}}}
+		_, err := conf.DirActions(expander)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "foreach [.vars.missing]: variable not found")
+	})
+}
@@ -0,0 +1,322 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2/unstable"
+	"golang.org/x/xerrors"
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+const (
+	tagStr   = "!!str"
+	tagInt   = "!!int"
+	tagFloat = "!!float"
+	tagBool  = "!!bool"
+	tagMap   = "!!map"
+	tagSeq   = "!!seq"
+)
+
+// FormatByPath returns the config format by the file extension ([entity.ConfigFormatYAML] by default).
+func FormatByPath(path string) entity.ConfigFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return entity.ConfigFormatJSON
+	case ".toml":
+		return entity.ConfigFormatTOML
+	default:
+		return entity.ConfigFormatYAML
+	}
+}
+
+// parseDocument parses the config data of the format to the `yaml` document node.
+// Nodes keep the positions and the declaration order of the original data.
+func parseDocument(format entity.ConfigFormat, data []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	switch format {
+	case entity.ConfigFormatTOML:
+		root, err := parseTOML(data)
+		if err != nil {
+			return nil, err
+		}
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}, Line: 1, Column: 1}
+		quoteStrings(&doc)
+	case entity.ConfigFormatJSON:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		// JSON is parsed as `yaml` flow nodes, the block style keeps each action on the own line
+		walkNodes(&doc, func(node *yaml.Node) {
+			node.Style = 0
+		})
+		quoteStrings(&doc)
+	default:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+	}
+	return &doc, nil
+}
+
+// quoteStrings sets the double-quoted style to the single-line string values, so the encoded values stay valid
+// `yaml` strings when the template variables contain the quotes (the plain and the single-quoted styles are not).
+func quoteStrings(doc *yaml.Node) {
+	quote := func(node *yaml.Node) {
+		if node.Kind == yaml.ScalarNode && node.Tag == tagStr && !strings.Contains(node.Value, entity.NewLine) {
+			node.Style = yaml.DoubleQuotedStyle
+		}
+	}
+	walkNodes(doc, func(node *yaml.Node) {
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 1; i < len(node.Content); i += 2 {
+				quote(node.Content[i])
+			}
+		case yaml.SequenceNode:
+			for _, item := range node.Content {
+				quote(item)
+			}
+		}
+	})
+}
+
+// parseTOML converts TOML document to the `yaml` mapping node.
+func parseTOML(data []byte) (*yaml.Node, error) {
+	var (
+		p = unstable.Parser{KeepComments: true}
+
+		root     = &yaml.Node{Kind: yaml.MappingNode, Tag: tagMap, Line: 1, Column: 1}
+		current  = root
+		comments []string
+	)
+
+	position := func(r unstable.Range) Position {
+		if r.Length == 0 {
+			return Position{}
+		}
+		start := p.Shape(r).Start
+		return Position{Line: start.Line, Column: start.Column}
+	}
+
+	p.Reset(data)
+	for p.NextExpression() {
+		expr := p.Expression()
+
+		var (
+			node *yaml.Node
+			err  error
+		)
+		switch expr.Kind {
+		case unstable.Comment:
+			comments = append(comments, string(expr.Data))
+			continue
+		case unstable.KeyValue:
+			node, err = tomlKeyValue(current, expr, position)
+		case unstable.Table:
+			current, node, err = tomlTable(root, expr, position, false)
+		case unstable.ArrayTable:
+			current, node, err = tomlTable(root, expr, position, true)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if node != nil {
+			node.HeadComment = joinComments(append(comments, node.HeadComment)...)
+			if comment := expr.Next(); comment != nil && comment.Kind == unstable.Comment {
+				node.LineComment = string(comment.Data)
+			}
+		}
+		comments = nil
+	}
+
+	if err := p.Error(); err != nil {
+		var parserErr *unstable.ParserError
+		if errors.As(err, &parserErr) && len(parserErr.Highlight) > 0 {
+			return nil, newSourceError(position(p.Range(parserErr.Highlight)), xerrors.Errorf("toml: %w", err))
+		}
+		return nil, xerrors.Errorf("toml: %w", err)
+	}
+	root.FootComment = joinComments(comments...)
+	return root, nil
+}
+
+type tomlPositionFn func(r unstable.Range) Position
+
+// tomlTable returns the mapping node of the table (or the new item of the array of tables) and the table's key node.
+func tomlTable(root *yaml.Node, expr *unstable.Node, position tomlPositionFn, array bool) (*yaml.Node, *yaml.Node, error) {
+	var (
+		keys    = tomlKeys(expr.Key(), position)
+		current = root
+		keyNode *yaml.Node
+	)
+	for i, key := range keys {
+		last := i == len(keys)-1
+		index := mappingIndex(current, key.Value)
+		if index < 0 {
+			val := &yaml.Node{Kind: yaml.MappingNode, Tag: tagMap, Line: key.Line, Column: key.Column}
+			if last && array {
+				val = &yaml.Node{Kind: yaml.SequenceNode, Tag: tagSeq, Line: key.Line, Column: key.Column}
+			}
+			current.Content = append(current.Content, key, val)
+			index = len(current.Content) - 2
+		}
+
+		keyNode = current.Content[index]
+		next := current.Content[index+1]
+		switch {
+		case last && array:
+			if next.Kind != yaml.SequenceNode {
+				return nil, nil, newSourceError(nodePosition(key), xerrors.Errorf("toml: key [%s] is not an array of tables", key.Value))
+			}
+			item := &yaml.Node{Kind: yaml.MappingNode, Tag: tagMap, Line: key.Line, Column: key.Column}
+			next.Content = append(next.Content, item)
+			next = item
+		case next.Kind == yaml.SequenceNode && len(next.Content) > 0:
+			// key of the array of tables refers to the last declared table
+			next = next.Content[len(next.Content)-1]
+		}
+
+		if next.Kind != yaml.MappingNode {
+			return nil, nil, newSourceError(nodePosition(key), xerrors.Errorf("toml: key [%s] is not a table", key.Value))
+		}
+		current = next
+	}
+	return current, keyNode, nil
+}
+
+func tomlKeyValue(table *yaml.Node, expr *unstable.Node, position tomlPositionFn) (*yaml.Node, error) {
+	var (
+		keys    = tomlKeys(expr.Key(), position)
+		current = table
+	)
+	for i, key := range keys {
+		index := mappingIndex(current, key.Value)
+		if i == len(keys)-1 {
+			if index >= 0 {
+				return nil, newSourceError(nodePosition(key), xerrors.Errorf("toml: key [%s] is already defined", key.Value))
+			}
+			val, err := tomlValue(expr.Value(), position)
+			if err != nil {
+				return nil, err
+			}
+			if val.Line == 0 {
+				val.Line, val.Column = key.Line, key.Column
+			}
+			current.Content = append(current.Content, key, val)
+			return key, nil
+		}
+
+		if index < 0 {
+			val := &yaml.Node{Kind: yaml.MappingNode, Tag: tagMap, Line: key.Line, Column: key.Column}
+			current.Content = append(current.Content, key, val)
+			index = len(current.Content) - 2
+		}
+		current = current.Content[index+1]
+		if current.Kind != yaml.MappingNode {
+			return nil, newSourceError(nodePosition(key), xerrors.Errorf("toml: key [%s] is not a table", key.Value))
+		}
+	}
+	return nil, nil
+}
+
+func tomlKeys(it unstable.Iterator, position tomlPositionFn) []*yaml.Node {
+	var keys []*yaml.Node
+	for it.Next() {
+		key := it.Node()
+		pos := position(key.Raw)
+		keys = append(keys, &yaml.Node{
+			Kind:   yaml.ScalarNode,
+			Tag:    tagStr,
+			Value:  string(key.Data),
+			Line:   pos.Line,
+			Column: pos.Column,
+		})
+	}
+	return keys
+}
+
+func tomlValue(value *unstable.Node, position tomlPositionFn) (*yaml.Node, error) {
+	var (
+		pos  = position(value.Raw)
+		node = &yaml.Node{Line: pos.Line, Column: pos.Column}
+	)
+
+	switch value.Kind {
+	case unstable.String:
+		node.Kind, node.Tag, node.Value = yaml.ScalarNode, tagStr, string(value.Data)
+		if strings.Contains(node.Value, entity.NewLine) {
+			node.Style = yaml.LiteralStyle
+		}
+	case unstable.Bool:
+		node.Kind, node.Tag, node.Value = yaml.ScalarNode, tagBool, string(value.Data)
+	case unstable.Integer:
+		raw := strings.ReplaceAll(string(value.Data), "_", entity.Empty)
+		i, err := strconv.ParseInt(raw, 0, 64)
+		if err != nil {
+			return nil, newSourceError(pos, xerrors.Errorf("toml: parse integer [%s]: %w", value.Data, err))
+		}
+		node.Kind, node.Tag, node.Value = yaml.ScalarNode, tagInt, strconv.FormatInt(i, 10)
+	case unstable.Float:
+		raw := strings.ReplaceAll(string(value.Data), "_", entity.Empty)
+		switch strings.TrimLeft(raw, "+-") {
+		case "inf":
+			raw = strings.Replace(raw, "inf", ".inf", 1)
+		case "nan":
+			raw = ".nan"
+		}
+		node.Kind, node.Tag, node.Value = yaml.ScalarNode, tagFloat, raw
+	case unstable.LocalDate, unstable.LocalTime, unstable.LocalDateTime, unstable.DateTime:
+		node.Kind, node.Tag, node.Value = yaml.ScalarNode, tagStr, string(value.Data)
+	case unstable.Array:
+		node.Kind, node.Tag = yaml.SequenceNode, tagSeq
+		for it := value.Children(); it.Next(); {
+			child := it.Node()
+			if child.Kind == unstable.Comment {
+				continue
+			}
+			item, err := tomlValue(child, position)
+			if err != nil {
+				return nil, err
+			}
+			if node.Line == 0 {
+				node.Line, node.Column = item.Line, item.Column
+			}
+			node.Content = append(node.Content, item)
+		}
+	case unstable.InlineTable:
+		node.Kind, node.Tag = yaml.MappingNode, tagMap
+		for it := value.Children(); it.Next(); {
+			child := it.Node()
+			if child.Kind != unstable.KeyValue {
+				continue
+			}
+			if _, err := tomlKeyValue(node, child, position); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, newSourceError(pos, xerrors.Errorf("toml: unsupported value [%s]", value.Kind))
+	}
+	return node, nil
+}
+
+// encodeDocument encodes the document node to the `yaml` data.
+func encodeDocument(doc *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
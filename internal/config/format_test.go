@@ -0,0 +1,128 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+func Test_FormatByPath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, entity.ConfigFormatYAML, FormatByPath("progen.yml"))
+	assert.Equal(t, entity.ConfigFormatYAML, FormatByPath("progen"))
+	assert.Equal(t, entity.ConfigFormatJSON, FormatByPath("conf/progen.JSON"))
+	assert.Equal(t, entity.ConfigFormatTOML, FormatByPath("conf/progen.toml"))
+}
+
+func Test_IncludeResolver_Resolve_format(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success_convert_toml", func(t *testing.T) {
+		const (
+			in = `cmd = ["echo {{ .vars.name }}"]
+
+# template variables
+[vars]
+name = "svc"
+port = 8_080
+debug = true
+
+[[files]]
+path = "a.txt"
+data = """
+port: {{ .vars.port }}
+"""
+
+[[files]]
+path = "b.txt" # file b
+data = "b"
+
+[settings.http]
+base_url = "https://some.com"
+`
+			expected = `cmd:
+  - "echo {{ .vars.name }}"
+# template variables
+vars:
+  name: "svc"
+  port: 8080
+  debug: true
+files:
+  - path: "a.txt"
+    data: |
+      port: {{ .vars.port }}
+  - path: "b.txt" # file b
+    data: "b"
+settings:
+  http:
+    base_url: "https://some.com"
+`
+		)
+		res, source, err := NewIncludeResolver(entity.ConfigFormatTOML, os.ReadFile).Resolve("progen.toml", []byte(in))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(res))
+
+		conf, err := NewYamlConfigUnmarshaler(true).Unmarshal(res)
+		assert.NoError(t, err)
+		assert.Len(t, conf.Files, 1)
+		assert.Len(t, conf.Files[0].Val, 2)
+
+		err = source.Locate(res, newSourceError(Position{Line: 12, Column: 5}, assert.AnError))
+		assert.Contains(t, err.Error(), "progen.toml:16:1: ")
+	})
+	t.Run("success_convert_json", func(t *testing.T) {
+		const (
+			in = `{
+  "vars": {"name": "svc"},
+  "dirs": ["a/{{ .vars.name }}"],
+  "cmd": [{"exec": "ls", "args": ["-a"]}]
+}`
+			expected = `vars:
+  name: "svc"
+dirs:
+  - "a/{{ .vars.name }}"
+cmd:
+  - exec: "ls"
+    args:
+      - "-a"
+`
+		)
+		res, _, err := NewIncludeResolver(entity.ConfigFormatJSON, os.ReadFile).Resolve("progen.json", []byte(in))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(res))
+	})
+	t.Run("success_process_quote_in_variable", func(t *testing.T) {
+		for format, in := range map[entity.ConfigFormat]string{
+			entity.ConfigFormatJSON: `{"vars":{"msg":"it's"},"files":[{"path":"r.txt","data":"{{.vars.msg}}"}]}`,
+			entity.ConfigFormatTOML: "[vars]\nmsg = \"it's\"\n\n[[files]]\npath = \"r.txt\"\ndata = \"{{.vars.msg}}\"\n",
+		} {
+			res, _, err := NewRawPreprocessor("progen", NewIncludeResolver(format, os.ReadFile), nil, nil, nil, nil, nil).
+				Process([]byte(in))
+			assert.NoError(t, err, format)
+
+			conf, err := NewYamlConfigUnmarshaler(true).Unmarshal(res)
+			assert.NoError(t, err, format)
+			assert.Equal(t, Bytes("it's"), *conf.Files[0].Val[0].Data, format)
+		}
+	})
+	t.Run("error_when_toml_is_invalid", func(t *testing.T) {
+		const (
+			in = "a = 1\nb = \n"
+		)
+		_, _, err := NewIncludeResolver(entity.ConfigFormatTOML, os.ReadFile).Resolve("progen.toml", []byte(in))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "progen.toml:2:5: toml:")
+	})
+	t.Run("error_when_toml_key_is_duplicated", func(t *testing.T) {
+		const (
+			in = "a = 1\na = 2\n"
+		)
+		_, _, err := NewIncludeResolver(entity.ConfigFormatTOML, os.ReadFile).Resolve("progen.toml", []byte(in))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "progen.toml:2:1: toml: key [a] is already defined")
+	})
+}
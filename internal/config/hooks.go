@@ -0,0 +1,71 @@
+package config
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+const (
+	tagHooks     = "hooks"
+	tagOnFailure = "on_failure"
+)
+
+var (
+	failureVarRegexp = regexp.MustCompile(`\.` + TemplateDataNamespace + `\.` + TemplateDataFailure + `\b`)
+)
+
+// Hooks are the commands executed before and after all actions and when the execution fails.
+// Commands of the `on_failure` hook can use the failure data: `.progen.failure.action`, `.progen.failure.error`.
+type Hooks struct {
+	BeforeAll []Command `yaml:"before_all"`
+	AfterAll  []Command `yaml:"after_all"`
+	OnFailure []Command `yaml:"on_failure"`
+}
+
+func (h *Hooks) commands() []Command {
+	if h == nil {
+		return nil
+	}
+	return slices.Concat(h.BeforeAll, h.AfterAll, h.OnFailure)
+}
+
+func sectionsHooks[T any](sections []Section[T]) []Command {
+	var commands []Command
+	for _, section := range sections {
+		commands = slices.Concat(commands, section.Before, section.After)
+	}
+	return commands
+}
+
+// protectFailureVars escapes the template actions, which use `.progen.failure` in the `settings.hooks.on_failure`
+// commands of the raw config, so the actions are processed only when the execution fails.
+func protectFailureVars(data []byte) []byte {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return data
+	}
+
+	var (
+		lines = strings.SplitAfter(string(data), entity.NewLine)
+		end   = len(lines)
+		node  = documentRoot(&doc)
+	)
+	// the last line of the hook is the line before the next tag of the hook's parents
+	for _, key := range []string{SettingsHTTP, tagHooks, tagOnFailure} {
+		i := mappingIndex(node, key)
+		if i < 0 {
+			return data
+		}
+		if i+2 < len(node.Content) {
+			end = node.Content[i+2].Line - 1
+		}
+		node = node.Content[i+1]
+	}
+	protectActions(lines, node.Line, end, failureVarRegexp)
+	return []byte(strings.Join(lines, entity.Empty))
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+func Test_protectFailureVars(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success_protect_on_failure_commands", func(t *testing.T) {
+		const (
+			in = `settings:
+  hooks:
+    before_all:
+      - echo {{ .vars.name }}
+    on_failure:
+      - echo {{ .vars.name }} {{.progen.failure.action}}
+      - exec: echo
+        args: [ "{{ if .progen.failure.error }}{{ .vars.name }}{{ end }}" ]
+  strict: true
+cmd:
+  - echo {{ .progen.failure.action }}
+`
+			expected = `settings:
+  hooks:
+    before_all:
+      - echo {{ .vars.name }}
+    on_failure:
+      - echo {{ .vars.name }} {{ ` + "`" + `{{.progen.failure.action}}` + "`" + ` }}
+      - exec: echo
+        args: [ "{{ ` + "`" + `{{ if .progen.failure.error }}` + "`" + ` }}{{ ` + "`" + `{{ .vars.name }}` + "`" + ` }}{{ ` + "`" + `{{ end }}` + "`" + ` }}" ]
+  strict: true
+cmd:
+  - echo {{ .progen.failure.action }}
+`
+		)
+		assert.Equal(t, expected, string(protectFailureVars([]byte(in))))
+	})
+	t.Run("success_protect_multi_line_actions_after_loop_vars", func(t *testing.T) {
+		const (
+			in = `files:
+  - path: "{{ .item }}"
+    foreach: .vars.list
+    data: data
+settings:
+  hooks:
+    on_failure:
+      - exec: sh
+        args: [ -c, "echo '{{ .progen.failure.action
+          }}' > failure.log" ]
+`
+			expected = `files:
+  - path: "{{ ` + "`" + `{{ .item }}` + "`" + ` }}"
+    foreach: .vars.list
+    data: data
+settings:
+  hooks:
+    on_failure:
+      - exec: sh
+        args: [ -c, "echo '{{ ` + "`" + `{{ .progen.failure.action
+          }}` + "`" + ` }}' > failure.log" ]
+`
+		)
+		assert.Equal(t, expected, string(protectFailureVars(protectLoopVars([]byte(in)))))
+	})
+	t.Run("success_skip_without_on_failure", func(t *testing.T) {
+		const in = "cmd:\n  - echo {{ .progen.failure.action }}\n"
+		assert.Equal(t, in, string(protectFailureVars([]byte(in))))
+	})
+}
+
+func Test_Config_hooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success_decode_hooks", func(t *testing.T) {
+		const (
+			in = `
+settings:
+  hooks:
+    before_all: [ echo start ]
+    on_failure:
+      - exec: echo
+        args: [ failed ]
+        when: .vars.notify
+cmd:
+  before: [ echo before ]
+  after: [ echo after ]
+  items:
+    - ls
+`
+		)
+		conf, err := NewYamlConfigUnmarshaler(true).Unmarshal([]byte(in))
+		assert.NoError(t, err)
+		assert.NoError(t, conf.Validate())
+
+		hooks := conf.Hooks()
+		assert.Equal(t, []entity.Command{{Cmd: "echo", Args: []string{"start"}}}, hooks.BeforeAll)
+		assert.Nil(t, hooks.AfterAll)
+		assert.Equal(t, []entity.Command{{Cmd: "echo", Args: []string{"failed"}, When: ".vars.notify"}}, hooks.OnFailure)
+
+		actions, err := conf.CommandActions(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.Command{{Cmd: "echo", Args: []string{"before"}}}, actions[0].Before)
+		assert.Equal(t, []entity.Command{{Cmd: "echo", Args: []string{"after"}}}, actions[0].After)
+	})
+	t.Run("error_when_hook_has_foreach", func(t *testing.T) {
+		const (
+			in = `
+dirs:
+  after:
+    - exec: echo
+      foreach: .vars.list
+  items: [ a ]
+`
+		)
+		conf, err := NewYamlConfigUnmarshaler(true).Unmarshal([]byte(in))
+		assert.NoError(t, err)
+		err = conf.Validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "hooks: line 4, column 7: `foreach` is not supported")
+	})
+}
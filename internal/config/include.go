@@ -0,0 +1,308 @@
+package config
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+const (
+	TagInclude = "include"
+	TagExtends = "extends"
+
+	tagSettingsGroups = "groups"
+	tagGroupName      = "name"
+)
+
+// IncludeResolver composes a config from the files declared in the `extends` and `include` root tags.
+// Declared files are merged in order: `extends`, each of `include`, the including config itself.
+//
+// Merge rules:
+//   - action sections (`dirs`, `rm`, `files`, `cmd`, `fs` with suffixes) are replaced by the same tag
+//     of the later file, new tags are appended in declaration order;
+//   - `settings` and any other sections (variables) are merged deeply, values of the later file win;
+//   - `settings.groups` are merged by the group's `name`.
+//
+// Config of the `json` or `toml` format is converted to the `yaml`, included files format is detected by the extension.
+type IncludeResolver struct {
+	format     entity.ConfigFormat
+	readFileFn func(path string) ([]byte, error)
+}
+
+func NewIncludeResolver(format entity.ConfigFormat, readFileFn func(path string) ([]byte, error)) *IncludeResolver {
+	return &IncludeResolver{
+		format:     format,
+		readFileFn: readFileFn,
+	}
+}
+
+// Resolve returns the composed config data or the input data when config contains no composition tags.
+// The returned [Source] locates the nodes of the composed config in the original files.
+func (r *IncludeResolver) Resolve(path string, data []byte) ([]byte, *Source, error) {
+	doc, err := parseDocument(r.format, data)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("parse config: %w", fileError(path, data, err))
+	}
+
+	root := documentRoot(doc)
+	source := newSource(path, data, root)
+	if root == nil || !hasComposition(root) && !r.converted() {
+		return data, source, nil
+	}
+
+	abs, err := absPath(path)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("resolve config path [%s]: %w", path, err)
+	}
+
+	source.addFile(path, data, root)
+	merged, err := r.resolveNode(source, path, root, []string{abs})
+	if err != nil {
+		return nil, nil, err
+	}
+	doc.Content = []*yaml.Node{merged}
+	source.root = merged
+
+	res, err := encodeDocument(doc)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("encode composed config [%s]: %w", path, err)
+	}
+	return res, source, nil
+}
+
+func (r *IncludeResolver) converted() bool {
+	return r.format != entity.Empty && r.format != entity.ConfigFormatYAML
+}
+
+func (r *IncludeResolver) resolveNode(source *Source, path string, root *yaml.Node, stack []string) (*yaml.Node, error) {
+	var (
+		parents []string
+		own     = &yaml.Node{Kind: yaml.MappingNode, Tag: root.Tag, Style: root.Style}
+	)
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, val := root.Content[i], root.Content[i+1]
+		switch key.Value {
+		case TagExtends:
+			if val.Kind != yaml.ScalarNode {
+				return nil, source.errorAt(path, nodePosition(val), xerrors.Errorf("`%s` must be a file path", TagExtends))
+			}
+			parents = append([]string{val.Value}, parents...)
+		case TagInclude:
+			includes, err := includePaths(val)
+			if err != nil {
+				return nil, source.errorAt(path, nodePosition(val), err)
+			}
+			parents = append(parents, includes...)
+		default:
+			own.Content = append(own.Content, key, val)
+			continue
+		}
+		// keeps `text/template` variables declared in comments of the composition tags
+		own.HeadComment = joinComments(own.HeadComment, key.HeadComment, key.LineComment, val.LineComment)
+	}
+
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: root.Tag}
+	for _, parent := range parents {
+		parentPath := includedPath(path, parent)
+		abs, err := absPath(parentPath)
+		if err != nil {
+			return nil, xerrors.Errorf("config [%s]: resolve included file path [%s]: %w", path, parentPath, err)
+		}
+
+		for _, p := range stack {
+			if p == abs {
+				return nil, xerrors.Errorf("include cycle: %s -> %s", strings.Join(stack, " -> "), abs)
+			}
+		}
+
+		data, err := r.readFileFn(abs)
+		if err != nil {
+			return nil, xerrors.Errorf("config [%s]: read included file: %w", path, err)
+		}
+
+		doc, err := parseDocument(FormatByPath(parentPath), data)
+		if err != nil {
+			return nil, xerrors.Errorf("config [%s]: parse included file: %w", path, fileError(parentPath, data, err))
+		}
+
+		parentRoot := documentRoot(doc)
+		if parentRoot == nil {
+			continue
+		}
+		parentRoot.HeadComment = joinComments(doc.HeadComment, parentRoot.HeadComment)
+		source.addFile(parentPath, data, parentRoot)
+
+		resolved, err := r.resolveNode(source, parentPath, parentRoot, append(stack, abs))
+		if err != nil {
+			return nil, err
+		}
+		mergeRoot(merged, resolved)
+	}
+
+	own.HeadComment = joinComments(root.HeadComment, own.HeadComment)
+	mergeRoot(merged, own)
+	return merged, nil
+}
+
+// includedPath returns the path of the included file relative to the including file (local file or url).
+func includedPath(path, included string) string {
+	if isRemote(path) && !isAbsRef(included) {
+		if base, err := url.Parse(path); err == nil {
+			return Origin{URL: base}.resolveURL(included)
+		}
+	}
+	if isRemote(included) || filepath.IsAbs(included) {
+		return included
+	}
+	return filepath.Join(filepath.Dir(path), included)
+}
+
+func absPath(path string) (string, error) {
+	if isRemote(path) {
+		return path, nil
+	}
+	return filepath.Abs(path)
+}
+
+func includePaths(node *yaml.Node) ([]string, error) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return []string{node.Value}, nil
+	case yaml.SequenceNode:
+		paths := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return nil, xerrors.Errorf("`%s` must contain only file paths", TagInclude)
+			}
+			paths = append(paths, item.Value)
+		}
+		return paths, nil
+	default:
+		return nil, xerrors.Errorf("`%s` must be a file path or list of file paths", TagInclude)
+	}
+}
+
+func mergeRoot(dst, src *yaml.Node) {
+	dst.HeadComment = joinComments(dst.HeadComment, src.HeadComment)
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, val := src.Content[i], src.Content[i+1]
+		index := mappingIndex(dst, key.Value)
+		switch {
+		case index < 0:
+			dst.Content = append(dst.Content, key, val)
+		case IsActionTag(key.Value):
+			dst.Content[index], dst.Content[index+1] = key, val
+		case key.Value == SettingsHTTP:
+			mergeSettings(dst.Content[index+1], val)
+		default:
+			dst.Content[index+1] = mergeNodes(dst.Content[index+1], val)
+		}
+	}
+}
+
+func mergeSettings(dst, src *yaml.Node) {
+	if dst.Kind != yaml.MappingNode || src.Kind != yaml.MappingNode {
+		*dst = *src
+		return
+	}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, val := src.Content[i], src.Content[i+1]
+		index := mappingIndex(dst, key.Value)
+		switch {
+		case index < 0:
+			dst.Content = append(dst.Content, key, val)
+		case key.Value == tagSettingsGroups:
+			dst.Content[index+1] = mergeGroups(dst.Content[index+1], val)
+		default:
+			dst.Content[index+1] = mergeNodes(dst.Content[index+1], val)
+		}
+	}
+}
+
+func mergeGroups(dst, src *yaml.Node) *yaml.Node {
+	if dst.Kind != yaml.SequenceNode || src.Kind != yaml.SequenceNode {
+		return src
+	}
+	for _, group := range src.Content {
+		var (
+			name     = groupName(group)
+			replaced bool
+		)
+		for i, existing := range dst.Content {
+			if name != entity.Empty && groupName(existing) == name {
+				dst.Content[i] = group
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			dst.Content = append(dst.Content, group)
+		}
+	}
+	return dst
+}
+
+func mergeNodes(dst, src *yaml.Node) *yaml.Node {
+	if dst.Kind != yaml.MappingNode || src.Kind != yaml.MappingNode {
+		return src
+	}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, val := src.Content[i], src.Content[i+1]
+		index := mappingIndex(dst, key.Value)
+		if index < 0 {
+			dst.Content = append(dst.Content, key, val)
+			continue
+		}
+		dst.Content[index+1] = mergeNodes(dst.Content[index+1], val)
+	}
+	return dst
+}
+
+func groupName(group *yaml.Node) string {
+	if index := mappingIndex(group, tagGroupName); index >= 0 {
+		return group.Content[index+1].Value
+	}
+	return entity.Empty
+}
+
+func mappingIndex(node *yaml.Node, key string) int {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func documentRoot(doc *yaml.Node) *yaml.Node {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil
+	}
+	if root := doc.Content[0]; root.Kind == yaml.MappingNode {
+		return root
+	}
+	return nil
+}
+
+func hasComposition(root *yaml.Node) bool {
+	return mappingIndex(root, TagInclude) >= 0 || mappingIndex(root, TagExtends) >= 0
+}
+
+func joinComments(comments ...string) string {
+	res := make([]string, 0, len(comments))
+	for _, comment := range comments {
+		if strings.TrimSpace(comment) != entity.Empty {
+			res = append(res, comment)
+		}
+	}
+	return strings.Join(res, entity.NewLine)
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+func Test_IncludeResolver_Resolve(t *testing.T) {
+	t.Parallel()
+
+	mockResolver := func(files map[string]string) *IncludeResolver {
+		return &IncludeResolver{
+			format: entity.ConfigFormatYAML,
+			readFileFn: func(path string) ([]byte, error) {
+				data, ok := files[path]
+				if !ok {
+					return nil, os.ErrNotExist
+				}
+				return []byte(data), nil
+			},
+		}
+	}
+
+	abs := func(t *testing.T, path string) string {
+		t.Helper()
+		res, err := filepath.Abs(path)
+		assert.NoError(t, err)
+		return res
+	}
+
+	t.Run("success_return_input_when_composition_tags_not_present", func(t *testing.T) {
+		const (
+			in = `
+dirs:
+  - a/b
+`
+		)
+		res, _, err := mockResolver(nil).Resolve("progen.yml", []byte(in))
+		assert.NoError(t, err)
+		assert.Equal(t, in, string(res))
+	})
+	t.Run("success_merge_extends_and_include", func(t *testing.T) {
+		const (
+			base = `
+settings:
+  http:
+    debug: true
+  groups:
+    - name: g1
+      actions: [cmd]
+    - name: g2
+      actions: [cmd]
+vars:
+  name: base
+  port: 80
+dirs:
+  - base
+cmd:
+  - echo base
+`
+			extra = `
+vars:
+  extra: true
+cmd2:
+  - echo extra
+`
+			in = `
+extends: shared/base.yml
+include: [extra.yml]
+settings:
+  groups:
+    - name: g1
+      actions: [cmd, cmd2]
+vars:
+  port: 8080
+cmd:
+  - echo child
+`
+			expected = `settings:
+  http:
+    debug: true
+  groups:
+    - name: g1
+      actions: [cmd, cmd2]
+    - name: g2
+      actions: [cmd]
+vars:
+  name: base
+  port: 8080
+  extra: true
+dirs:
+  - base
+cmd:
+  - echo child
+cmd2:
+  - echo extra
+`
+		)
+
+		resolver := mockResolver(map[string]string{
+			abs(t, "conf/shared/base.yml"): base,
+			abs(t, "conf/extra.yml"):       extra,
+		})
+		res, _, err := resolver.Resolve("conf/progen.yml", []byte(in))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(res))
+	})
+	t.Run("success_resolve_nested_include_relative_to_including_file", func(t *testing.T) {
+		const (
+			in       = `include: [shared/a.yml]`
+			a        = `include: [b.yml]`
+			b        = `dirs: [b]`
+			expected = "dirs: [b]\n"
+		)
+
+		resolver := mockResolver(map[string]string{
+			abs(t, "shared/a.yml"): a,
+			abs(t, "shared/b.yml"): b,
+		})
+		res, _, err := resolver.Resolve("progen.yml", []byte(in))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(res))
+	})
+	t.Run("error_when_include_cycle", func(t *testing.T) {
+		const (
+			in = `include: [a.yml]`
+			a  = `include: [progen.yml]`
+		)
+
+		resolver := mockResolver(map[string]string{
+			abs(t, "a.yml"):      a,
+			abs(t, "progen.yml"): in,
+		})
+		_, _, err := resolver.Resolve("progen.yml", []byte(in))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "include cycle")
+	})
+	t.Run("error_when_included_file_not_exists", func(t *testing.T) {
+		const (
+			in = `extends: not_exists.yml`
+		)
+		_, _, err := mockResolver(nil).Resolve("progen.yml", []byte(in))
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func Test_includedPath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, filepath.Join("conf", "shared", "a.yml"), includedPath(filepath.Join("conf", "progen.yml"), "shared/a.yml"))
+	assert.Equal(t, "https://some.com/conf/shared/a.yml", includedPath("https://some.com/conf/progen.yml", "shared/a.yml"))
+	assert.Equal(t, "https://some.com/a.yml", includedPath("https://some.com/conf/progen.yml", "../a.yml"))
+	assert.Equal(t, "https://other.com/a.yml", includedPath("progen.yml", "https://other.com/a.yml"))
+}
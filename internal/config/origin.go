@@ -0,0 +1,183 @@
+package config
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	resty "github.com/go-resty/resty/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+const (
+	schemeHTTP  = "http://"
+	schemeHTTPS = "https://"
+	schemeFile  = "file://"
+	prefixGit   = "git::"
+
+	gitSubpathSep = "//"
+	gitRefParam   = "ref"
+)
+
+// Origin is the location of the config which is loaded by the reference (`http(s)://`, `file://`, `git::`).
+// Relative `local` and `get.url` of the files are resolved against the origin.
+type Origin struct {
+	// URL of the config loaded by `http(s)://` reference.
+	URL *url.URL
+	// Dir is the local directory of the config loaded by `file://` or `git::` reference.
+	Dir string
+}
+
+// ResolveFiles resolves relative `local` and `get.url` of the files against the origin:
+//   - `local` of the remote config is loaded by the url relative to the config url;
+//   - `get.url` of the remote config is resolved against the config url when `settings.http.base_url` is not set;
+//   - `local` of the `file://` or `git::` config is resolved against the config directory.
+func (o Origin) ResolveFiles(actions []entity.Action[[]entity.UndefinedFile], httpConf *HTTPClient) []entity.Action[[]entity.UndefinedFile] {
+	if o.URL == nil && o.Dir == entity.Empty {
+		return actions
+	}
+
+	baseURLSet := httpConf != nil && httpConf.BaseURL.URL != nil && httpConf.BaseURL.String() != entity.Empty
+	for i, action := range actions {
+		for j, file := range action.Val {
+			switch {
+			case file.Local != nil && o.URL != nil && !isAbsRef(*file.Local):
+				file.Get = &entity.HTTPClientParams{URL: o.resolveURL(*file.Local)}
+				file.Local = nil
+			case file.Local != nil && o.Dir != entity.Empty && !filepath.IsAbs(*file.Local):
+				local := filepath.Join(o.Dir, *file.Local)
+				file.Local = &local
+			case file.Get != nil && o.URL != nil && !baseURLSet && !isAbsRef(file.Get.URL):
+				get := *file.Get
+				get.URL = o.resolveURL(get.URL)
+				file.Get = &get
+			}
+			actions[i].Val[j] = file
+		}
+	}
+	return actions
+}
+
+func (o Origin) resolveURL(ref string) string {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return o.URL.ResolveReference(u).String()
+}
+
+func isAbsRef(ref string) bool {
+	u, err := url.Parse(ref)
+	return (err == nil && u.IsAbs()) || filepath.IsAbs(ref)
+}
+
+func isRemote(path string) bool {
+	return strings.HasPrefix(path, schemeHTTP) || strings.HasPrefix(path, schemeHTTPS)
+}
+
+// gitReference is the `git::<repository>//<path>?ref=<ref>` config reference.
+type gitReference struct {
+	Repo string
+	Path string
+	Ref  string
+}
+
+func parseGitReference(ref string) (gitReference, error) {
+	raw, ok := strings.CutPrefix(ref, prefixGit)
+	if !ok {
+		return gitReference{}, xerrors.Errorf("git reference must start with `%s`: %s", prefixGit, ref)
+	}
+
+	var res gitReference
+	if before, query, found := strings.Cut(raw, "?"); found {
+		values, err := url.ParseQuery(query)
+		if err != nil {
+			return gitReference{}, xerrors.Errorf("parse git reference query [%s]: %w", ref, err)
+		}
+		raw, res.Ref = before, values.Get(gitRefParam)
+	}
+
+	// skips the `://` of the repository url scheme
+	from := 0
+	if i := strings.Index(raw, "://"); i >= 0 {
+		from = i + len("://")
+	}
+	i := strings.Index(raw[from:], gitSubpathSep)
+	if i < 0 {
+		return gitReference{}, xerrors.Errorf("git reference must contain config path after `%s`: %s", gitSubpathSep, ref)
+	}
+	res.Repo, res.Path = raw[:from+i], strings.Trim(raw[from+i+len(gitSubpathSep):], "/")
+	if res.Repo == entity.Empty || res.Path == entity.Empty {
+		return gitReference{}, xerrors.Errorf("git reference repository and config path must not be empty: %s", ref)
+	}
+	return res, nil
+}
+
+// gitClone clones the repository to the directory and checkouts the reference.
+func gitClone(ref gitReference, dir string) error {
+	run := func(args ...string) error {
+		var stderr bytes.Buffer
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return xerrors.Errorf("git %s: %s: %w", strings.Join(args, entity.Space), strings.TrimSpace(stderr.String()), err)
+		}
+		return nil
+	}
+
+	if ref.Ref == entity.Empty {
+		return run("clone", "--depth", "1", "--quiet", ref.Repo, entity.Dot)
+	}
+
+	// fetch allows to checkout branch, tag or commit
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"remote", "add", "origin", ref.Repo},
+		{"fetch", "--depth", "1", "--quiet", "origin", ref.Ref},
+		{"checkout", "--quiet", "FETCH_HEAD"},
+	} {
+		if err := run(args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getRemote(client *resty.Client, rawURL string) ([]byte, error) {
+	rs, err := client.R().Get(rawURL)
+	if err != nil {
+		return nil, xerrors.Errorf("get [%s]: %w", rawURL, err)
+	}
+	if code := rs.StatusCode(); code < http.StatusOK || code >= http.StatusMultipleChoices {
+		return nil, xerrors.Errorf("get [%s]: status [%d]: response status is not in the 2xx range", rawURL, code)
+	}
+	return rs.Body(), nil
+}
+
+func fileURLPath(ref string) (string, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return entity.Empty, xerrors.Errorf("parse file reference [%s]: %w", ref, err)
+	}
+	p := u.Path
+	if u.Host != entity.Empty && u.Host != "localhost" {
+		// `file://relative/path` declares the relative path
+		p = path.Join(u.Host, p)
+	}
+	return filepath.FromSlash(p), nil
+}
+
+func removeDir(dir string) error {
+	if dir == entity.Empty {
+		return nil
+	}
+	return os.RemoveAll(dir)
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	resty "github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+func Test_parseGitReference(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success_with_url_and_ref", func(t *testing.T) {
+		ref, err := parseGitReference("git::https://github.com/some/repo.git//templates/progen.yml?ref=v1.0.0")
+		assert.NoError(t, err)
+		assert.Equal(t, gitReference{
+			Repo: "https://github.com/some/repo.git",
+			Path: "templates/progen.yml",
+			Ref:  "v1.0.0",
+		}, ref)
+	})
+	t.Run("success_with_scp_like_repository", func(t *testing.T) {
+		ref, err := parseGitReference("git::git@github.com:some/repo.git//progen.yml")
+		assert.NoError(t, err)
+		assert.Equal(t, gitReference{
+			Repo: "git@github.com:some/repo.git",
+			Path: "progen.yml",
+		}, ref)
+	})
+	t.Run("error_when_path_not_set", func(t *testing.T) {
+		_, err := parseGitReference("git::https://github.com/some/repo.git?ref=main")
+		assert.Error(t, err)
+	})
+}
+
+func Test_Origin_ResolveFiles(t *testing.T) {
+	t.Parallel()
+
+	newActions := func(files ...entity.UndefinedFile) []entity.Action[[]entity.UndefinedFile] {
+		return []entity.Action[[]entity.UndefinedFile]{{Name: TagFiles, Val: files}}
+	}
+	strPtr := func(s string) *string {
+		return &s
+	}
+
+	t.Run("remote_origin", func(t *testing.T) {
+		origin := Origin{URL: &url.URL{Scheme: "https", Host: "some.com", Path: "/templates/progen.yml"}}
+		actions := origin.ResolveFiles(newActions(
+			entity.UndefinedFile{Path: "a", Local: strPtr("tmpl/a.txt")},
+			entity.UndefinedFile{Path: "b", Get: &entity.HTTPClientParams{URL: "../b.txt"}},
+			entity.UndefinedFile{Path: "c", Get: &entity.HTTPClientParams{URL: "https://other.com/c.txt"}},
+		), nil)
+
+		files := actions[0].Val
+		assert.Nil(t, files[0].Local)
+		assert.Equal(t, "https://some.com/templates/tmpl/a.txt", files[0].Get.URL)
+		assert.Equal(t, "https://some.com/b.txt", files[1].Get.URL)
+		assert.Equal(t, "https://other.com/c.txt", files[2].Get.URL)
+	})
+	t.Run("remote_origin_with_base_url", func(t *testing.T) {
+		var (
+			origin  = Origin{URL: &url.URL{Scheme: "https", Host: "some.com", Path: "/progen.yml"}}
+			baseURL = &url.URL{Scheme: "https", Host: "api.com"}
+		)
+		actions := origin.ResolveFiles(newActions(
+			entity.UndefinedFile{Path: "b", Get: &entity.HTTPClientParams{URL: "b.txt"}},
+		), &HTTPClient{BaseURL: AddrURL{URL: baseURL}})
+		assert.Equal(t, "b.txt", actions[0].Val[0].Get.URL)
+	})
+	t.Run("local_origin", func(t *testing.T) {
+		origin := Origin{Dir: filepath.Join("repo", "templates")}
+		actions := origin.ResolveFiles(newActions(
+			entity.UndefinedFile{Path: "a", Local: strPtr("a.txt")},
+			entity.UndefinedFile{Path: "b", Get: &entity.HTTPClientParams{URL: "b.txt"}},
+		), nil)
+
+		files := actions[0].Val
+		assert.Equal(t, filepath.Join("repo", "templates", "a.txt"), *files[0].Local)
+		assert.Equal(t, "b.txt", files[1].Get.URL)
+	})
+}
+
+func Test_Reader_references(t *testing.T) {
+	t.Parallel()
+
+	const (
+		data = "dirs: [a]\n"
+	)
+
+	t.Run("success_read_url", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/templates/progen.toml", r.URL.Path)
+			_, _ = w.Write([]byte(data))
+		}))
+		defer server.Close()
+
+		reader := Reader{path: server.URL + "/templates/progen.toml", client: resty.New()}
+		res, err := reader.Read()
+		assert.NoError(t, err)
+		assert.Equal(t, data, string(res))
+		assert.Equal(t, entity.ConfigFormatTOML, reader.Format())
+		assert.Equal(t, server.URL+"/templates/progen.toml", reader.Origin().URL.String())
+	})
+	t.Run("error_when_url_status_is_not_2xx", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		reader := Reader{path: server.URL + "/progen.yml", client: resty.New()}
+		_, err := reader.Read()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "status [404]")
+	})
+	t.Run("success_read_file_reference", func(t *testing.T) {
+		var (
+			dir  = t.TempDir()
+			path = filepath.Join(dir, "progen.yml")
+		)
+		assert.NoError(t, os.WriteFile(path, []byte(data), os.ModePerm))
+
+		reader := Reader{path: "file://" + filepath.ToSlash(path)}
+		res, err := reader.Read()
+		assert.NoError(t, err)
+		assert.Equal(t, data, string(res))
+		assert.Equal(t, path, reader.Path())
+		assert.Equal(t, dir, reader.Origin().Dir)
+	})
+	t.Run("success_read_git_reference", func(t *testing.T) {
+		reader := Reader{
+			path: "git::https://github.com/some/repo.git//templates/progen.json?ref=main",
+			gitCloneFn: func(ref gitReference, dir string) error {
+				assert.Equal(t, "https://github.com/some/repo.git", ref.Repo)
+				assert.Equal(t, "main", ref.Ref)
+				assert.NoError(t, os.Mkdir(filepath.Join(dir, "templates"), os.ModePerm))
+				return os.WriteFile(filepath.Join(dir, "templates", "progen.json"), []byte(data), os.ModePerm)
+			},
+		}
+		res, err := reader.Read()
+		assert.NoError(t, err)
+		assert.Equal(t, data, string(res))
+		assert.Equal(t, entity.ConfigFormatJSON, reader.Format())
+		assert.Equal(t, filepath.Join(reader.tmpDir, "templates"), reader.Origin().Dir)
+
+		assert.NoError(t, reader.Close())
+		assert.NoDirExists(t, reader.tmpDir)
+	})
+}
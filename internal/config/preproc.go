@@ -12,14 +12,29 @@ type RawPreprocessor struct {
 	templateVars    map[string]any
 	templateFns     map[string]any
 	templateOptions []string
+	profiles        []string
+
+	includeResolver *IncludeResolver
+	prompter        *Prompter
+	source          *Source
 }
 
-func NewRawPreprocessor(templateName string, templateVars, templateFns map[string]any, templateOptions []string) *RawPreprocessor {
+func NewRawPreprocessor(
+	templateName string,
+	includeResolver *IncludeResolver,
+	templateVars, templateFns map[string]any,
+	templateOptions []string,
+	profiles []string,
+	prompter *Prompter,
+) *RawPreprocessor {
 	return &RawPreprocessor{
 		templateName:    templateName,
 		templateVars:    templateVars,
 		templateFns:     templateFns,
 		templateOptions: templateOptions,
+		profiles:        profiles,
+		includeResolver: includeResolver,
+		prompter:        prompter,
 	}
 }
 
@@ -29,17 +44,81 @@ func (p *RawPreprocessor) Process(data []byte) ([]byte, map[string]any, error) {
 		name = p.templateName
 	)
 
-	err := yaml.Unmarshal(data, &conf)
+	data, source, err := p.includeResolver.Resolve(name, data)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("compose config: %w", err)
+	}
+	p.source = source
+
+	err = yaml.Unmarshal(data, &conf)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("parse config to map: %w", source.Locate(data, withYamlPosition(err)))
+	}
+
+	profiles, err := selectProfiles(data, p.profiles)
 	if err != nil {
-		return nil, nil, xerrors.Errorf("parse config to map: %w", err)
+		return nil, nil, xerrors.Errorf("profiles: %w", source.Locate(data, err))
 	}
 
+	var answers map[string]any
+	if p.prompter != nil {
+		prompts, err := decodePrompts(data)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("prompts: %w", source.Locate(data, err))
+		}
+		answers, err = p.prompter.Ask(prompts, p.templateVars)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("prompts: %w", source.Locate(data, err))
+		}
+	}
+
+	// variables overrides: config <- profiles (in order) <- prompts answers <- flags
+	conf = entity.MergeKeys(conf, profiles.Vars())
+	conf = entity.MergeKeys(conf, answers)
 	conf = entity.MergeKeys(conf, p.templateVars)
+	conf = entity.MergeKeys(conf, map[string]any{
+		TemplateDataNamespace: map[string]any{
+			TemplateDataProfiles: profilesNames(profiles),
+		},
+	})
 
-	res, err := entity.NewTemplateProc(conf, p.templateFns, p.templateOptions).Process(name, string(data))
+	res, err := entity.NewTemplateProc(conf, p.templateFns, p.templateOptions).Process(name, string(protectFailureVars(protectLoopVars(data))))
 	if err != nil {
+		if pos, ok := templateErrorPosition(name, err); ok {
+			err = source.Locate(data, newSourceError(pos, err))
+		}
 		return nil, nil, xerrors.Errorf("config data: %w", err)
 	}
 
 	return []byte(res), conf, nil
 }
+
+// Source returns the source of the last processed config to locate errors of the processed config.
+func (p *RawPreprocessor) Source() *Source {
+	return p.source
+}
+
+// selectProfiles returns the profiles of the `settings` selected by names.
+func selectProfiles(data []byte, names []string) (Profiles, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	var conf struct {
+		Settings struct {
+			Profiles Profiles `yaml:"profiles"`
+		} `yaml:"settings"`
+	}
+	if err := yaml.Unmarshal(data, &conf); err != nil {
+		return nil, xerrors.Errorf("unmarshal: %w", withYamlPosition(err))
+	}
+	return conf.Settings.Profiles.Select(names)
+}
+
+func profilesNames(profiles Profiles) []string {
+	names := make([]string, 0, len(profiles))
+	for _, profile := range profiles {
+		names = append(names, profile.Name)
+	}
+	return names
+}
@@ -0,0 +1,298 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+type PromptType string
+
+const (
+	PromptTypeString      PromptType = "string"
+	PromptTypeBool        PromptType = "bool"
+	PromptTypeInt         PromptType = "int"
+	PromptTypeChoice      PromptType = "choice"
+	PromptTypeMultiChoice PromptType = "multi-choice"
+)
+
+// Prompt declares the template variable which value is asked before the config processing.
+type Prompt struct {
+	Path     string     `yaml:"path"`
+	Question string     `yaml:"question"`
+	Type     PromptType `yaml:"type"`
+	Default  any        `yaml:"default"`
+	Regex    string     `yaml:"regex"`
+	Enum     []string   `yaml:"enum"`
+
+	Pos Position `yaml:"-"`
+}
+
+func (p *Prompt) UnmarshalYAML(node *yaml.Node) error {
+	type alias Prompt
+	var prompt alias
+	if err := node.Decode(&prompt); err != nil {
+		return err
+	}
+	*p = (Prompt)(prompt)
+	p.Pos = nodePosition(node)
+	return nil
+}
+
+func (p Prompt) promptType() PromptType {
+	if p.Type == entity.Empty {
+		return PromptTypeString
+	}
+	return p.Type
+}
+
+func (p Prompt) validate() error {
+	switch {
+	case len(entity.VarPath(p.Path)) == 0:
+		return xerrors.Errorf("prompt `path` is empty")
+	case !slices.Contains([]PromptType{PromptTypeString, PromptTypeBool, PromptTypeInt, PromptTypeChoice, PromptTypeMultiChoice}, p.promptType()):
+		return xerrors.Errorf("prompt [%s]: unknown type [%s]", p.Path, p.Type)
+	case (p.promptType() == PromptTypeChoice || p.promptType() == PromptTypeMultiChoice) && len(p.Enum) == 0:
+		return xerrors.Errorf("prompt [%s]: `enum` is empty for type [%s]", p.Path, p.Type)
+	}
+	if _, err := regexp.Compile(p.Regex); err != nil {
+		return xerrors.Errorf("prompt [%s]: invalid `regex`: %w", p.Path, err)
+	}
+	if answer, ok := p.defaultAnswer(); ok {
+		if _, err := p.parse(answer); err != nil {
+			return xerrors.Errorf("prompt [%s]: invalid `default`: %w", p.Path, err)
+		}
+	}
+	return nil
+}
+
+// defaultAnswer returns the default value as the answer text, false if the default value is not set.
+func (p Prompt) defaultAnswer() (string, bool) {
+	switch val := p.Default.(type) {
+	case nil:
+		return entity.Empty, false
+	case []any:
+		values := make([]string, len(val))
+		for i, v := range val {
+			values[i] = fmt.Sprint(v)
+		}
+		return strings.Join(values, entity.Comma), true
+	default:
+		return fmt.Sprint(val), true
+	}
+}
+
+// parse converts the answer to the value of the prompt type and validates it.
+func (p Prompt) parse(answer string) (any, error) {
+	answer = strings.TrimSpace(answer)
+	if p.Regex != entity.Empty {
+		matched, err := regexp.MatchString(p.Regex, answer)
+		if err != nil {
+			return nil, xerrors.Errorf("match regex [%s]: %w", p.Regex, err)
+		}
+		if !matched {
+			return nil, xerrors.Errorf("[%s] does not match regex [%s]", answer, p.Regex)
+		}
+	}
+
+	switch p.promptType() {
+	case PromptTypeBool:
+		switch strings.ToLower(answer) {
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		val, err := strconv.ParseBool(answer)
+		if err != nil {
+			return nil, xerrors.Errorf("[%s] is not a bool", answer)
+		}
+		return val, nil
+	case PromptTypeInt:
+		val, err := strconv.Atoi(answer)
+		if err != nil {
+			return nil, xerrors.Errorf("[%s] is not an int", answer)
+		}
+		return val, nil
+	case PromptTypeChoice:
+		return p.choice(answer)
+	case PromptTypeMultiChoice:
+		var values []any
+		for _, item := range strings.Split(answer, entity.Comma) {
+			if item = strings.TrimSpace(item); item == entity.Empty {
+				continue
+			}
+			val, err := p.choice(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, val)
+		}
+		return values, nil
+	default:
+		if len(p.Enum) > 0 && !slices.Contains(p.Enum, answer) {
+			return nil, xerrors.Errorf("[%s] is not one of [%s]", answer, strings.Join(p.Enum, entity.LogSliceSep))
+		}
+		return answer, nil
+	}
+}
+
+// choice returns the `enum` value selected by the value or the number of the option.
+func (p Prompt) choice(answer string) (string, error) {
+	if slices.Contains(p.Enum, answer) {
+		return answer, nil
+	}
+	if n, err := strconv.Atoi(answer); err == nil && n > 0 && n <= len(p.Enum) {
+		return p.Enum[n-1], nil
+	}
+	return entity.Empty, xerrors.Errorf("[%s] is not one of [%s]", answer, strings.Join(p.Enum, entity.LogSliceSep))
+}
+
+type Prompts []Prompt
+
+// Prompter asks the values of the prompts.
+type Prompter struct {
+	in          *bufio.Reader
+	out         io.Writer
+	interactive bool
+}
+
+// NewPrompter creates the [Prompter]. Not interactive prompter uses default values of the prompts.
+func NewPrompter(in io.Reader, out io.Writer, interactive bool) *Prompter {
+	return &Prompter{
+		in:          bufio.NewReader(in),
+		out:         out,
+		interactive: interactive,
+	}
+}
+
+// Ask returns the variables tree of the answers. Prompts which paths are present in the provided variables are skipped.
+func (p *Prompter) Ask(prompts Prompts, provided map[string]any) (map[string]any, error) {
+	answers := make(map[string]any)
+	for _, prompt := range prompts {
+		if err := prompt.validate(); err != nil {
+			return nil, newSourceError(prompt.Pos, err)
+		}
+		if _, ok := entity.LookupVar(provided, prompt.Path); ok {
+			continue
+		}
+
+		var (
+			val any
+			err error
+		)
+		if p.interactive {
+			val, err = p.ask(prompt)
+		} else {
+			val, err = p.defaultValue(prompt)
+		}
+		if err != nil {
+			return nil, newSourceError(prompt.Pos, err)
+		}
+		answers = entity.SetVar(answers, prompt.Path, val)
+	}
+	return answers, nil
+}
+
+// Choose asks to select one of the options. Not interactive prompter returns the default option.
+func (p *Prompter) Choose(question string, options []string, defaultOption string) (string, error) {
+	if !p.interactive {
+		return defaultOption, nil
+	}
+	val, err := p.ask(Prompt{
+		Path:     question,
+		Question: question,
+		Type:     PromptTypeChoice,
+		Enum:     options,
+		Default:  defaultOption,
+	})
+	if err != nil {
+		return entity.Empty, err
+	}
+	return val.(string), nil
+}
+
+func (p *Prompter) defaultValue(prompt Prompt) (any, error) {
+	answer, ok := prompt.defaultAnswer()
+	if !ok {
+		return nil, xerrors.Errorf("prompt [%s]: answer is required, but input is disabled", prompt.Path)
+	}
+	return prompt.parse(answer)
+}
+
+func (p *Prompter) ask(prompt Prompt) (any, error) {
+	question := prompt.Question
+	if question == entity.Empty {
+		question = prompt.Path
+	}
+	defaultAnswer, hasDefault := prompt.defaultAnswer()
+
+	for {
+		_, _ = fmt.Fprint(p.out, question)
+		switch prompt.promptType() {
+		case PromptTypeChoice, PromptTypeMultiChoice:
+			_, _ = fmt.Fprintln(p.out, ":")
+			for i, option := range prompt.Enum {
+				_, _ = fmt.Fprintf(p.out, "  %d) %s\n", i+1, option)
+			}
+			if prompt.promptType() == PromptTypeMultiChoice {
+				_, _ = fmt.Fprint(p.out, "select (comma separated)")
+			} else {
+				_, _ = fmt.Fprint(p.out, "select")
+			}
+		case PromptTypeBool:
+			_, _ = fmt.Fprint(p.out, " (y/n)")
+		}
+		if hasDefault {
+			_, _ = fmt.Fprintf(p.out, " [%s]", defaultAnswer)
+		}
+		_, _ = fmt.Fprint(p.out, ": ")
+
+		line, err := p.in.ReadString('\n')
+		if err != nil && (err != io.EOF || line == entity.Empty) {
+			if err == io.EOF && hasDefault {
+				_, _ = fmt.Fprintln(p.out)
+				return prompt.parse(defaultAnswer)
+			}
+			return nil, xerrors.Errorf("prompt [%s]: read answer: %w", prompt.Path, err)
+		}
+
+		answer := strings.TrimSpace(line)
+		if answer == entity.Empty {
+			if !hasDefault {
+				_, _ = fmt.Fprintln(p.out, "answer is required")
+				continue
+			}
+			answer = defaultAnswer
+		}
+
+		val, err := prompt.parse(answer)
+		if err != nil {
+			_, _ = fmt.Fprintf(p.out, "invalid answer: %v\n", err)
+			continue
+		}
+		return val, nil
+	}
+}
+
+// decodePrompts returns the prompts of the `settings`.
+func decodePrompts(data []byte) (Prompts, error) {
+	var conf struct {
+		Settings struct {
+			Prompts Prompts `yaml:"prompts"`
+		} `yaml:"settings"`
+	}
+	if err := yaml.Unmarshal(data, &conf); err != nil {
+		return nil, xerrors.Errorf("unmarshal: %w", withYamlPosition(err))
+	}
+	return conf.Settings.Prompts, nil
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+func Test_Prompt_parse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success_parse_types", func(t *testing.T) {
+		val, err := Prompt{Path: "a", Type: PromptTypeBool}.parse("yes")
+		assert.NoError(t, err)
+		assert.Equal(t, true, val)
+
+		val, err = Prompt{Path: "a", Type: PromptTypeInt}.parse(" 8080 ")
+		assert.NoError(t, err)
+		assert.Equal(t, 8080, val)
+
+		val, err = Prompt{Path: "a", Type: PromptTypeChoice, Enum: []string{"pg", "mysql"}}.parse("2")
+		assert.NoError(t, err)
+		assert.Equal(t, "mysql", val)
+
+		val, err = Prompt{Path: "a", Type: PromptTypeMultiChoice, Enum: []string{"a", "b", "c"}}.parse("c, 1")
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"c", "a"}, val)
+	})
+	t.Run("error_when_regex_not_matched", func(t *testing.T) {
+		_, err := Prompt{Path: "a", Regex: "^[a-z]+$"}.parse("Some")
+		assert.Error(t, err)
+		assert.Equal(t, "[Some] does not match regex [^[a-z]+$]", err.Error())
+	})
+	t.Run("error_when_not_in_enum", func(t *testing.T) {
+		_, err := Prompt{Path: "a", Enum: []string{"a", "b"}}.parse("c")
+		assert.Error(t, err)
+		assert.Equal(t, "[c] is not one of [a, b]", err.Error())
+	})
+	t.Run("error_validate", func(t *testing.T) {
+		assert.Error(t, Prompt{}.validate())
+		assert.Error(t, Prompt{Path: "a", Type: "float"}.validate())
+		assert.Error(t, Prompt{Path: "a", Type: PromptTypeChoice}.validate())
+		assert.Error(t, Prompt{Path: "a", Regex: "("}.validate())
+		assert.Error(t, Prompt{Path: "a", Type: PromptTypeInt, Default: "a"}.validate())
+		assert.NoError(t, Prompt{Path: "a", Type: PromptTypeMultiChoice, Enum: []string{"a", "b"}, Default: []any{"a", "b"}}.validate())
+	})
+}
+
+func Test_Prompter_Ask(t *testing.T) {
+	t.Parallel()
+
+	prompts := Prompts{
+		{Path: ".vars.name", Question: "Name", Regex: "^[a-z]+$"},
+		{Path: ".vars.port", Type: PromptTypeInt, Default: 8080},
+		{Path: ".vars.db", Question: "Database", Type: PromptTypeChoice, Enum: []string{"pg", "mysql"}, Default: "pg"},
+	}
+
+	t.Run("success_ask_interactive", func(t *testing.T) {
+		var out bytes.Buffer
+		answers, err := NewPrompter(strings.NewReader("Svc\nsvc\n\n2\n"), &out, true).Ask(prompts, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"vars": map[string]any{"name": "svc", "port": 8080, "db": "mysql"}}, answers)
+		assert.Equal(t, "Name: invalid answer: [Svc] does not match regex [^[a-z]+$]\n"+
+			"Name: .vars.port [8080]: Database:\n  1) pg\n  2) mysql\nselect [pg]: ", out.String())
+	})
+	t.Run("success_skip_provided", func(t *testing.T) {
+		provided := map[string]any{"vars": map[string]any{"name": "svc"}}
+		answers, err := NewPrompter(strings.NewReader("9090\n"), io.Discard, true).Ask(prompts, provided)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"vars": map[string]any{"port": 9090, "db": "pg"}}, answers)
+	})
+	t.Run("error_when_required_answer_missing_without_input", func(t *testing.T) {
+		_, err := NewPrompter(strings.NewReader(entity.Empty), io.Discard, false).Ask(prompts, nil)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "prompt [.vars.name]: answer is required")
+	})
+	t.Run("success_defaults_without_input", func(t *testing.T) {
+		provided := map[string]any{"vars": map[string]any{"name": "svc"}}
+		answers, err := NewPrompter(strings.NewReader(entity.Empty), io.Discard, false).Ask(prompts, provided)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"vars": map[string]any{"port": 8080, "db": "pg"}}, answers)
+	})
+	t.Run("success_choose", func(t *testing.T) {
+		options := []string{"skip", "overwrite"}
+		answer, err := NewPrompter(strings.NewReader("2\n"), io.Discard, true).Choose("File exists", options, "skip")
+		assert.NoError(t, err)
+		assert.Equal(t, "overwrite", answer)
+
+		answer, err = NewPrompter(strings.NewReader(entity.Empty), io.Discard, false).Choose("File exists", options, "skip")
+		assert.NoError(t, err)
+		assert.Equal(t, "skip", answer)
+	})
+}
+
+func Test_RawPreprocessor_prompts(t *testing.T) {
+	t.Parallel()
+
+	const (
+		name = "progen.yml"
+		in   = `
+settings:
+  prompts:
+    - path: .vars.name
+      question: Service name
+    - path: .vars.debug
+      type: bool
+      default: false
+vars:
+  name: default
+dirs:
+  - "{{ .vars.name }}/{{ .vars.debug }}"
+`
+	)
+
+	newPreprocessor := func(templateVars map[string]any, input string, interactive bool) *RawPreprocessor {
+		return NewRawPreprocessor(
+			name,
+			NewIncludeResolver(entity.ConfigFormatYAML, os.ReadFile),
+			templateVars,
+			nil,
+			nil,
+			nil,
+			NewPrompter(strings.NewReader(input), io.Discard, interactive),
+		)
+	}
+
+	t.Run("success_apply_answers", func(t *testing.T) {
+		res, _, err := newPreprocessor(nil, "svc\ny\n", true).Process([]byte(in))
+		assert.NoError(t, err)
+		assert.Contains(t, string(res), `- "svc/true"`)
+	})
+	t.Run("success_flags_vars_skip_prompts", func(t *testing.T) {
+		vars := map[string]any{"vars": map[string]any{"name": "flag"}}
+		res, _, err := newPreprocessor(vars, entity.Empty, false).Process([]byte(in))
+		assert.NoError(t, err)
+		assert.Contains(t, string(res), `- "flag/false"`)
+	})
+	t.Run("error_when_required_answer_missing", func(t *testing.T) {
+		_, _, err := newPreprocessor(nil, entity.Empty, false).Process([]byte(in))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "progen.yml:4:7: prompt [.vars.name]: answer is required")
+	})
+}
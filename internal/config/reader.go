@@ -4,8 +4,12 @@ import (
 	"bufio"
 	"errors"
 	"io"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 
+	resty "github.com/go-resty/resty/v2"
 	"golang.org/x/xerrors"
 
 	"github.com/kozmod/progen/internal/entity"
@@ -19,26 +23,65 @@ var (
 type Reader struct {
 	reader io.Reader
 	path   string
+	format entity.ConfigFormat
+	client *resty.Client
+	origin Origin
+	tmpDir string
+
+	gitCloneFn func(ref gitReference, dir string) error
 }
 
-func NewConfigReader(f flag.Flags) *Reader {
+func NewConfigReader(f flag.Flags, client *resty.Client) *Reader {
+	format := entity.ConfigFormat(f.Format)
 	if f.ReadStdin {
+		if format == entity.Empty {
+			format = entity.ConfigFormatYAML
+		}
 		return &Reader{
 			reader: os.Stdin,
+			path:   f.FileLocationMessage(),
+			format: format,
 		}
 	}
 	return &Reader{
-		path: f.ConfigPath,
+		path:       f.ConfigPath,
+		format:     format,
+		client:     client,
+		gitCloneFn: gitClone,
 	}
 }
 
+// Format returns the format of the config: set by the flag or detected by the file extension.
+func (r *Reader) Format() entity.ConfigFormat {
+	return r.format
+}
+
+// Path returns the path (or url) of the read config to locate errors and to resolve included files.
+func (r *Reader) Path() string {
+	return r.path
+}
+
+// Origin returns the location of the config loaded by the reference.
+func (r *Reader) Origin() Origin {
+	return r.origin
+}
+
+// ReadFile reads the local file or the remote file when the path is `http(s)` url.
+func (r *Reader) ReadFile(path string) ([]byte, error) {
+	if isRemote(path) {
+		return getRemote(r.client, path)
+	}
+	return os.ReadFile(path)
+}
+
+// Close removes the temporary files of the reader (cloned repository of the `git::` reference).
+func (r *Reader) Close() error {
+	return removeDir(r.tmpDir)
+}
+
 func (r *Reader) Read() ([]byte, error) {
 	if r.reader == nil {
-		data, err := os.ReadFile(r.path)
-		if err != nil {
-			return nil, xerrors.Errorf("config file: %w", err)
-		}
-		return data, nil
+		return r.readPath()
 	}
 
 	reader := bufio.NewReader(r.reader)
@@ -56,3 +99,47 @@ func (r *Reader) Read() ([]byte, error) {
 		}
 	}
 }
+
+func (r *Reader) readPath() ([]byte, error) {
+	formatPath := r.path
+	switch {
+	case isRemote(r.path):
+		u, err := url.Parse(r.path)
+		if err != nil {
+			return nil, xerrors.Errorf("config url: %w", err)
+		}
+		r.origin.URL, formatPath = u, u.Path
+	case strings.HasPrefix(r.path, schemeFile):
+		path, err := fileURLPath(r.path)
+		if err != nil {
+			return nil, xerrors.Errorf("config file: %w", err)
+		}
+		r.path, r.origin.Dir = path, filepath.Dir(path)
+		formatPath = path
+	case strings.HasPrefix(r.path, prefixGit):
+		ref, err := parseGitReference(r.path)
+		if err != nil {
+			return nil, xerrors.Errorf("config git reference: %w", err)
+		}
+		r.tmpDir, err = os.MkdirTemp(entity.Empty, "progen-git-")
+		if err != nil {
+			return nil, xerrors.Errorf("config git reference: create temporary dir: %w", err)
+		}
+		if err = r.gitCloneFn(ref, r.tmpDir); err != nil {
+			return nil, xerrors.Errorf("config git reference: clone [%s]: %w", ref.Repo, err)
+		}
+		path := filepath.Join(r.tmpDir, filepath.FromSlash(ref.Path))
+		r.path, r.origin.Dir = path, filepath.Dir(path)
+		formatPath = path
+	}
+
+	if r.format == entity.Empty {
+		r.format = FormatByPath(formatPath)
+	}
+
+	data, err := r.ReadFile(r.path)
+	if err != nil {
+		return nil, xerrors.Errorf("config file: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,212 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+
+	"golang.org/x/xerrors"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+const (
+	schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+	schemaTitle = "progen configuration"
+	schemaDefs  = "#/$defs/"
+)
+
+type (
+	jsonSchema = map[string]any
+
+	// sectionValue is implemented by [Section] to declare the types of the section's value and object form.
+	sectionValue interface {
+		valueType() reflect.Type
+		objectType() reflect.Type
+	}
+)
+
+var (
+	// schemaRequired contains required fields of the types, which can't be declared by the `yaml` tags.
+	schemaRequired = map[reflect.Type][]string{
+		reflect.TypeOf(File{}):    {"path"},
+		reflect.TypeOf(Dir{}):     {"path"},
+		reflect.TypeOf(Fs{}):      {"path"},
+		reflect.TypeOf(Link{}):    {"path", "target"},
+		reflect.TypeOf(Copy{}):    {"src", "dst"},
+		reflect.TypeOf(Group{}):   {"name", "actions"},
+		reflect.TypeOf(Profile{}): {"name"},
+		reflect.TypeOf(Prompt{}):  {"path"},
+	}
+)
+
+// Schema returns JSON Schema of the config.
+func Schema() ([]byte, error) {
+	schema := newSchemaGenerator().config()
+	res, err := json.MarshalIndent(schema, entity.Empty, "  ")
+	if err != nil {
+		return nil, xerrors.Errorf("marshal config schema: %w", err)
+	}
+	return res, nil
+}
+
+type schemaGenerator struct {
+	defs map[string]any
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		defs: make(map[string]any),
+	}
+}
+
+func (s *schemaGenerator) config() jsonSchema {
+	var (
+		typ        = reflect.TypeOf(Config{})
+		properties = jsonSchema{
+			TagExtends: jsonSchema{"type": "string", "description": "path of the extended config"},
+			TagInclude: jsonSchema{
+				"description": "paths of the included configs",
+				"oneOf": []any{
+					jsonSchema{"type": "string"},
+					jsonSchema{"type": "array", "items": jsonSchema{"type": "string"}},
+				},
+			},
+		}
+		patterns = jsonSchema{}
+	)
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, ok := yamlFieldName(field)
+		if !ok {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Slice {
+			if section, ok := reflect.New(field.Type.Elem()).Interface().(sectionValue); ok {
+				// action tags are declared with any suffix: `dirs`, `dirs1`, `dirs_some`
+				object := s.structSchema(section.objectType())
+				object["required"] = []string{"items"}
+				patterns["^"+regexp.QuoteMeta(name)] = jsonSchema{
+					"oneOf": []any{s.schema(section.valueType()), object},
+				}
+				continue
+			}
+		}
+		properties[name] = s.schema(field.Type)
+	}
+
+	return jsonSchema{
+		"$schema":           schemaDraft,
+		"title":             schemaTitle,
+		"type":              "object",
+		"properties":        properties,
+		"patternProperties": patterns,
+		// any other root tags are the template variables
+		"additionalProperties": true,
+		"$defs":                s.defs,
+	}
+}
+
+func (s *schemaGenerator) schema(typ reflect.Type) jsonSchema {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch typ {
+	case reflect.TypeOf(Bytes{}):
+		return jsonSchema{"type": "string"}
+	case reflect.TypeOf(AddrURL{}):
+		return jsonSchema{"type": "string", "format": "uri"}
+	case reflect.TypeOf(FileMode(0)):
+		return jsonSchema{
+			"description": "octal permissions",
+			"oneOf": []any{
+				jsonSchema{"type": "string", "pattern": "^(0o)?[0-7]{1,4}$"},
+				jsonSchema{"type": "integer"},
+			},
+		}
+	case reflect.TypeOf(entity.OnExists(entity.Empty)):
+		return jsonSchema{"type": "string", "enum": []entity.OnExists{
+			entity.OnExistsOverwrite,
+			entity.OnExistsSkip,
+			entity.OnExistsError,
+			entity.OnExistsBackup,
+			entity.OnExistsAppend,
+			entity.OnExistsPrepend,
+			entity.OnExistsPrompt,
+		}}
+	case reflect.TypeOf(Command{}):
+		return s.ref(typ, func() jsonSchema {
+			return jsonSchema{
+				"oneOf": []any{
+					jsonSchema{"type": "string", "description": "command with arguments separated by spaces"},
+					s.structSchema(typ),
+				},
+			}
+		})
+	case reflect.TypeOf(Dir{}), reflect.TypeOf(Fs{}):
+		return s.ref(typ, func() jsonSchema {
+			return jsonSchema{
+				"oneOf": []any{
+					jsonSchema{"type": "string", "description": "directory path"},
+					s.structSchema(typ),
+				},
+			}
+		})
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return jsonSchema{"type": "string"}
+	case reflect.Bool:
+		return jsonSchema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return jsonSchema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return jsonSchema{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return jsonSchema{"type": "array", "items": s.schema(typ.Elem())}
+	case reflect.Map:
+		return jsonSchema{"type": "object", "additionalProperties": s.schema(typ.Elem())}
+	case reflect.Struct:
+		return s.ref(typ, func() jsonSchema { return s.structSchema(typ) })
+	default:
+		return jsonSchema{}
+	}
+}
+
+// ref declares the named type schema in the `$defs` and returns the reference to it.
+func (s *schemaGenerator) ref(typ reflect.Type, schemaFn func() jsonSchema) jsonSchema {
+	name := typ.Name()
+	if name == entity.Empty {
+		return schemaFn()
+	}
+	if _, ok := s.defs[name]; !ok {
+		s.defs[name] = jsonSchema{} // prevents recursion
+		s.defs[name] = schemaFn()
+	}
+	return jsonSchema{"$ref": schemaDefs + name}
+}
+
+func (s *schemaGenerator) structSchema(typ reflect.Type) jsonSchema {
+	fields := make(map[string]reflect.Type, typ.NumField())
+	yamlFields(typ, fields)
+
+	properties := make(jsonSchema, len(fields))
+	for name, fieldType := range fields {
+		properties[name] = s.schema(fieldType)
+	}
+
+	schema := jsonSchema{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if required, ok := schemaRequired[typ]; ok {
+		schema["required"] = required
+	}
+	return schema
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Schema(t *testing.T) {
+	t.Parallel()
+
+	data, err := Schema()
+	assert.NoError(t, err)
+
+	var schema map[string]any
+	assert.NoError(t, json.Unmarshal(data, &schema))
+
+	t.Run("action_tags_declared_as_patterns", func(t *testing.T) {
+		patterns, ok := schema["patternProperties"].(map[string]any)
+		assert.True(t, ok)
+		for _, tag := range []string{TagDirs, TagRm, TagFiles, TagCmd, TagFS, TagLinks, TagCopy} {
+			assert.Contains(t, patterns, "^"+tag)
+		}
+
+		oneOf := patterns["^"+TagFiles].(map[string]any)["oneOf"].([]any)
+		assert.Len(t, oneOf, 2)
+		assert.Equal(t, map[string]any{"$ref": "#/$defs/File"}, oneOf[0].(map[string]any)["items"])
+
+		object := oneOf[1].(map[string]any)
+		assert.Equal(t, []any{"items"}, object["required"])
+		assert.ElementsMatch(t, []string{"when", "depends_on", "parallel", "before", "after", "items"}, keys(object["properties"].(map[string]any)))
+	})
+	t.Run("types_declared_by_yaml_tags", func(t *testing.T) {
+		defs, ok := schema["$defs"].(map[string]any)
+		assert.True(t, ok)
+		for _, def := range []string{"Settings", "File", "Dir", "Command", "Group", "HTTPClient", "Get"} {
+			assert.Contains(t, defs, def)
+		}
+
+		file := defs["File"].(map[string]any)
+		assert.Equal(t, []any{"path"}, file["required"])
+		assert.Equal(t, false, file["additionalProperties"])
+		assert.ElementsMatch(t, []string{"path", "data", "get", "local", "when", "foreach", "on_exists", "mode", "template"}, keys(file["properties"].(map[string]any)))
+		assert.Contains(t, file["properties"].(map[string]any)["on_exists"].(map[string]any)["enum"], "skip")
+
+		http := defs["HTTPClient"].(map[string]any)
+		assert.ElementsMatch(t, []string{"base_url", "debug", "headers", "query_params"}, keys(http["properties"].(map[string]any)))
+	})
+}
+
+func keys(m map[string]any) []string {
+	res := make([]string, 0, len(m))
+	for key := range m {
+		res = append(res, key)
+	}
+	return res
+}
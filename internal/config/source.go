@@ -0,0 +1,328 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+const (
+	snippetLinesBefore = 1
+)
+
+var (
+	yamlErrLineRegexp = regexp.MustCompile(`line (\d+):`)
+)
+
+// Position of the node in the config source.
+type Position struct {
+	Line   int
+	Column int
+}
+
+func nodePosition(node *yaml.Node) Position {
+	return Position{Line: node.Line, Column: node.Column}
+}
+
+// SourceError is an error located in the config.
+// Until the error is located by the [Source], the position is relative to the processed config.
+type SourceError struct {
+	Position
+	File    string
+	Snippet string
+	Err     error
+}
+
+func newSourceError(pos Position, err error) *SourceError {
+	return &SourceError{Position: pos, Err: err}
+}
+
+func (e *SourceError) Error() string {
+	var location string
+	switch {
+	case e.File != entity.Empty && e.Column > 0:
+		location = fmt.Sprintf("%s:%d:%d", e.File, e.Line, e.Column)
+	case e.File != entity.Empty:
+		location = fmt.Sprintf("%s:%d", e.File, e.Line)
+	case e.Column > 0:
+		location = fmt.Sprintf("line %d, column %d", e.Line, e.Column)
+	default:
+		location = fmt.Sprintf("line %d", e.Line)
+	}
+
+	msg := fmt.Sprintf("%s: %v", location, e.Err)
+	if e.Snippet != entity.Empty {
+		msg += entity.NewLine + e.Snippet
+	}
+	return msg
+}
+
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
+
+// Source keeps the original config files to locate errors of the composed or processed config.
+type Source struct {
+	name  string
+	data  map[string][]byte
+	root  *yaml.Node
+	files map[*yaml.Node]string
+}
+
+func newSource(name string, data []byte, root *yaml.Node) *Source {
+	return &Source{
+		name:  name,
+		data:  map[string][]byte{name: data},
+		root:  root,
+		files: make(map[*yaml.Node]string),
+	}
+}
+
+func (s *Source) addFile(name string, data []byte, root *yaml.Node) {
+	s.data[name] = data
+	walkNodes(root, func(node *yaml.Node) {
+		s.files[node] = name
+	})
+}
+
+// Locate sets the original file, position and the source snippet to the [SourceError] of the chain.
+// The position of the error is mapped from the derived config (composed or processed) by the YAML node path.
+func (s *Source) Locate(derived []byte, err error) error {
+	var srcErr *SourceError
+	if s == nil || !errors.As(err, &srcErr) || srcErr.File != entity.Empty {
+		return err
+	}
+
+	file, pos := s.locate(derived, srcErr.Position)
+	srcErr.File = file
+	srcErr.Position = pos
+	srcErr.Snippet = snippet(s.data[file], pos)
+	return err
+}
+
+// errorAt returns the [SourceError] located in the original file.
+func (s *Source) errorAt(file string, pos Position, err error) error {
+	return &SourceError{
+		Position: pos,
+		File:     file,
+		Snippet:  snippet(s.data[file], pos),
+		Err:      err,
+	}
+}
+
+func (s *Source) locate(derived []byte, pos Position) (string, Position) {
+	if s.root == nil {
+		return s.name, pos
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(derived, &doc); err != nil || len(doc.Content) == 0 {
+		return s.name, pos
+	}
+
+	path := nodePath(doc.Content[0], pos)
+	if len(path) == 0 {
+		return s.name, pos
+	}
+
+	var (
+		derivedNode = path[len(path)-1].node
+		srcNode     = s.root
+		resolved    = true
+	)
+	for _, elem := range path[1:] {
+		next := elem.follow(srcNode)
+		if next == nil {
+			resolved = false
+			break
+		}
+		srcNode = next
+	}
+
+	file, ok := s.files[srcNode]
+	if !ok {
+		file = s.name
+	}
+
+	located := nodePosition(srcNode)
+	if !resolved {
+		return file, located
+	}
+	if pos.Line == derivedNode.Line && pos.Column >= derivedNode.Column {
+		located.Column += pos.Column - derivedNode.Column
+	}
+	located.Line += pos.Line - derivedNode.Line
+	return file, located
+}
+
+type pathElem struct {
+	node  *yaml.Node
+	key   string
+	index int
+	isKey bool
+}
+
+func (e pathElem) follow(parent *yaml.Node) *yaml.Node {
+	switch parent.Kind {
+	case yaml.MappingNode:
+		index := mappingIndex(parent, e.key)
+		if index < 0 {
+			return nil
+		}
+		if e.isKey {
+			return parent.Content[index]
+		}
+		return parent.Content[index+1]
+	case yaml.SequenceNode:
+		if e.index < len(parent.Content) {
+			return parent.Content[e.index]
+		}
+	}
+	return nil
+}
+
+// nodePath returns path to the deepest node which starts at the position
+// or to the nearest node declared before the position.
+func nodePath(root *yaml.Node, pos Position) []pathElem {
+	var (
+		best    []pathElem
+		current = []pathElem{{node: root}}
+		exact   bool
+	)
+
+	var walk func(node *yaml.Node)
+	walk = func(node *yaml.Node) {
+		switch {
+		case node.Line == pos.Line && (pos.Column == 0 || node.Column == pos.Column):
+			best, exact = append([]pathElem(nil), current...), true
+		case !exact && (node.Line < pos.Line || node.Line == pos.Line && node.Column <= pos.Column):
+			best = append([]pathElem(nil), current...)
+		}
+
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key, val := node.Content[i], node.Content[i+1]
+				current = append(current, pathElem{node: key, key: key.Value, isKey: true})
+				walk(key)
+				current[len(current)-1] = pathElem{node: val, key: key.Value}
+				walk(val)
+				current = current[:len(current)-1]
+			}
+		case yaml.SequenceNode:
+			for i, item := range node.Content {
+				current = append(current, pathElem{node: item, index: i})
+				walk(item)
+				current = current[:len(current)-1]
+			}
+		}
+	}
+	walk(root)
+	return best
+}
+
+func walkNodes(node *yaml.Node, fn func(node *yaml.Node)) {
+	if node == nil {
+		return
+	}
+	fn(node)
+	for _, child := range node.Content {
+		walkNodes(child, fn)
+	}
+}
+
+// snippet returns the source lines before the position and the caret pointing to the column.
+func snippet(data []byte, pos Position) string {
+	lines := strings.Split(string(data), entity.NewLine)
+	if pos.Line < 1 || pos.Line > len(lines) {
+		return entity.Empty
+	}
+
+	var (
+		sb    strings.Builder
+		from  = max(pos.Line-snippetLinesBefore, 1)
+		width = len(strconv.Itoa(pos.Line))
+	)
+	for i := from; i <= pos.Line; i++ {
+		sb.WriteString(fmt.Sprintf(" %*d | %s\n", width, i, strings.TrimRight(lines[i-1], "\r")))
+	}
+	if pos.Column > 0 {
+		sb.WriteString(fmt.Sprintf(" %*s | %s^", width, entity.Empty, strings.Repeat(entity.Space, pos.Column-1)))
+	}
+	return strings.TrimRight(sb.String(), entity.NewLine)
+}
+
+// yamlErrorPosition returns the position of the first `yaml` error which contains line number.
+func yamlErrorPosition(err error) (Position, bool) {
+	match := yamlErrLineRegexp.FindStringSubmatch(err.Error())
+	if len(match) < 2 {
+		return Position{}, false
+	}
+	line, convErr := strconv.Atoi(match[1])
+	if convErr != nil {
+		return Position{}, false
+	}
+	return Position{Line: line}, true
+}
+
+// withYamlPosition wraps the `yaml` error to the [SourceError] when the error contains line number.
+func withYamlPosition(err error) error {
+	if err == nil {
+		return nil
+	}
+	var srcErr *SourceError
+	if errors.As(err, &srcErr) {
+		return err
+	}
+	if pos, ok := yamlErrorPosition(err); ok {
+		return newSourceError(pos, err)
+	}
+	return err
+}
+
+// fileError returns the parse error located in the file.
+func fileError(file string, data []byte, err error) error {
+	var srcErr *SourceError
+	if errors.As(err, &srcErr) && srcErr.File == entity.Empty {
+		srcErr.File = file
+		srcErr.Snippet = snippet(data, srcErr.Position)
+		return err
+	}
+
+	pos, ok := yamlErrorPosition(err)
+	if !ok {
+		return xerrors.Errorf("%s: %w", file, err)
+	}
+	return &SourceError{
+		Position: pos,
+		File:     file,
+		Snippet:  snippet(data, pos),
+		Err:      err,
+	}
+}
+
+// templateErrorPosition returns position of the `text/template` parse or execution error.
+func templateErrorPosition(name string, err error) (Position, bool) {
+	re, reErr := regexp.Compile(`template: ` + regexp.QuoteMeta(name) + `:(\d+):(?:(\d+):)?`)
+	if reErr != nil {
+		return Position{}, false
+	}
+	match := re.FindStringSubmatch(err.Error())
+	if len(match) < 2 {
+		return Position{}, false
+	}
+
+	var pos Position
+	pos.Line, _ = strconv.Atoi(match[1])
+	if len(match) > 2 && match[2] != entity.Empty {
+		pos.Column, _ = strconv.Atoi(match[2])
+	}
+	return pos, pos.Line > 0
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+func Test_Source_Locate(t *testing.T) {
+	t.Parallel()
+
+	const (
+		name     = "progen.yml"
+		original = `dirs:
+  - "{{ range .vars.dirs }}{{ . }} {{ end }}"
+files:
+  - path: a
+`
+		processed = `dirs:
+  - x
+  - y
+  - z
+files:
+  - path: a
+`
+	)
+
+	newTestSource := func(t *testing.T) *Source {
+		t.Helper()
+		_, source, err := NewIncludeResolver(entity.ConfigFormatYAML, os.ReadFile).Resolve(name, []byte(original))
+		assert.NoError(t, err)
+		return source
+	}
+
+	t.Run("success_locate_error_of_the_processed_config", func(t *testing.T) {
+		err := xerrors.Errorf("validate: %w", newSourceError(Position{Line: 6, Column: 5}, xerrors.New("some error")))
+
+		err = newTestSource(t).Locate([]byte(processed), err)
+
+		var srcErr *SourceError
+		assert.ErrorAs(t, err, &srcErr)
+		assert.Equal(t, name, srcErr.File)
+		assert.Equal(t, Position{Line: 4, Column: 5}, srcErr.Position)
+		assert.Equal(t, " 3 | files:\n 4 |   - path: a\n   |     ^", srcErr.Snippet)
+		assert.Contains(t, err.Error(), "progen.yml:4:5: some error")
+	})
+	t.Run("success_locate_error_without_column", func(t *testing.T) {
+		var err error = newSourceError(Position{Line: 5}, xerrors.New("some error"))
+
+		err = newTestSource(t).Locate([]byte(processed), err)
+
+		var srcErr *SourceError
+		assert.ErrorAs(t, err, &srcErr)
+		assert.Equal(t, 3, srcErr.Line)
+		assert.Contains(t, err.Error(), "progen.yml:3:1: some error")
+	})
+	t.Run("success_return_error_when_position_not_set", func(t *testing.T) {
+		in := xerrors.New("some error")
+		err := newTestSource(t).Locate([]byte(processed), in)
+		assert.Equal(t, in, err)
+	})
+	t.Run("success_return_error_when_source_is_nil", func(t *testing.T) {
+		var (
+			source *Source
+			in     = newSourceError(Position{Line: 1}, xerrors.New("some error"))
+		)
+		err := source.Locate([]byte(processed), in)
+		assert.Equal(t, "line 1: some error", err.Error())
+	})
+}
+
+func Test_templateErrorPosition(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success_execute_error", func(t *testing.T) {
+		err := xerrors.New(`process template: template: progen.yml:4:14: executing "progen.yml" at <.vars.x>: map has no entry for key "x"`)
+		pos, ok := templateErrorPosition("progen.yml", err)
+		assert.True(t, ok)
+		assert.Equal(t, Position{Line: 4, Column: 14}, pos)
+	})
+	t.Run("success_parse_error", func(t *testing.T) {
+		err := xerrors.New(`process template: template: progen.yml:7: unexpected "}" in operand`)
+		pos, ok := templateErrorPosition("progen.yml", err)
+		assert.True(t, ok)
+		assert.Equal(t, Position{Line: 7}, pos)
+	})
+	t.Run("not_found", func(t *testing.T) {
+		_, ok := templateErrorPosition("progen.yml", xerrors.New("some error"))
+		assert.False(t, ok)
+	})
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"reflect"
 	"strings"
 
 	"golang.org/x/xerrors"
@@ -13,55 +14,122 @@ var (
 	ErrCommandEmpty = xerrors.Errorf("command declaration is empty")
 )
 
-type YamlUnmarshaler struct{}
+type YamlUnmarshaler struct {
+	strict bool
+}
 
-func NewYamlConfigUnmarshaler() *YamlUnmarshaler {
-	return &YamlUnmarshaler{}
+func NewYamlConfigUnmarshaler(strict bool) *YamlUnmarshaler {
+	return &YamlUnmarshaler{
+		strict: strict,
+	}
 }
 
 func (u *YamlUnmarshaler) Unmarshal(rawConfig []byte) (Config, error) {
+	conf, errs := u.unmarshal(rawConfig, false)
+	if len(errs) > 0 {
+		return conf, errs[0]
+	}
+	return conf, nil
+}
+
+// UnmarshalAll returns the config and all errors of the root tags (including all unknown tags and fields in `strict` mode)
+// instead of the first one.
+func (u *YamlUnmarshaler) UnmarshalAll(rawConfig []byte) (Config, []error) {
+	return u.unmarshal(rawConfig, true)
+}
+
+func (u *YamlUnmarshaler) unmarshal(rawConfig []byte, all bool) (Config, []error) {
 	var (
-		conf     Config
-		rootTags map[string]yaml.Node
+		conf Config
+		doc  yaml.Node
+		errs []error
 	)
 
-	if err := yaml.Unmarshal(rawConfig, &rootTags); err != nil {
-		return conf, xerrors.Errorf("unmarshal url: %w", err)
+	if err := yaml.Unmarshal(rawConfig, &doc); err != nil {
+		return conf, []error{xerrors.Errorf("unmarshal config: %w", withYamlPosition(err))}
+	}
+
+	root := documentRoot(&doc)
+	if root == nil {
+		return conf, nil
+	}
+
+	if index := mappingIndex(root, SettingsHTTP); index >= 0 {
+		if err := root.Content[index+1].Decode(&conf.Settings); err != nil {
+			return conf, []error{xerrors.Errorf("unmarshal tag [%s]: %w", SettingsHTTP, withYamlPosition(err))}
+		}
+	}
+
+	strict := u.strict || conf.Settings.Strict
+	if strict {
+		for _, err := range checkRootTags(root, conf.Settings.Namespaces) {
+			errs = append(errs, xerrors.Errorf("strict: %w", err))
+		}
 	}
 
-	for tag, node := range rootTags {
-		var err error
+	for i := 0; i+1 < len(root.Content) && (all || len(errs) == 0); i += 2 {
+		var (
+			tag     = root.Content[i].Value
+			node    = *root.Content[i+1]
+			tagErrs []error
+		)
 
 		switch {
 		case tag == SettingsHTTP:
-			var settings Settings
-			err = node.Decode(&settings)
-			conf.Settings = settings
+			if strict {
+				tagErrs = checkKnownFields(root.Content[i+1], reflect.TypeOf(conf.Settings))
+			}
 		case strings.Index(tag, TagDirs) == 0:
-			conf.Dirs, err = decode(conf.Dirs, node, tag)
+			conf.Dirs, tagErrs = decode(conf.Dirs, node, tag, strict)
 		case strings.Index(tag, TagRm) == 0:
-			conf.Rm, err = decode(conf.Rm, node, tag)
+			conf.Rm, tagErrs = decode(conf.Rm, node, tag, strict)
 		case strings.Index(tag, TagFiles) == 0:
-			conf.Files, err = decode(conf.Files, node, tag)
+			conf.Files, tagErrs = decode(conf.Files, node, tag, strict)
 		case strings.Index(tag, TagCmd) == 0:
-			conf.Cmd, err = decode(conf.Cmd, node, tag)
+			conf.Cmd, tagErrs = decode(conf.Cmd, node, tag, strict)
 		case strings.Index(tag, TagFS) == 0:
-			conf.FS, err = decode(conf.FS, node, tag)
+			conf.FS, tagErrs = decode(conf.FS, node, tag, strict)
+		case strings.Index(tag, TagLinks) == 0:
+			conf.Links, tagErrs = decode(conf.Links, node, tag, strict)
+		case strings.Index(tag, TagCopy) == 0:
+			conf.Copy, tagErrs = decode(conf.Copy, node, tag, strict)
 		}
 
-		if err != nil {
-			return conf, xerrors.Errorf("unmarshal tag [%s]: %w", tag, err)
+		for _, err := range tagErrs {
+			errs = append(errs, xerrors.Errorf("unmarshal tag [%s]: %w", tag, err))
 		}
 	}
 
-	return conf, nil
+	return conf, errs
 }
 
-func decode[T any](target []Section[T], node yaml.Node, tag string) ([]Section[T], error) {
-	section := Section[T]{Line: node.Line, Tag: tag}
-	err := node.Decode(&section.Val)
+// IsActionTag reports whether the root tag declares an action section (tag with any suffix).
+func IsActionTag(tag string) bool {
+	for _, prefix := range []string{TagDirs, TagRm, TagFiles, TagCmd, TagFS, TagLinks, TagCopy} {
+		if strings.HasPrefix(tag, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func decode[T any](target []Section[T], node yaml.Node, tag string, strict bool) ([]Section[T], []error) {
+	section := Section[T]{Line: node.Line, Column: node.Column, Tag: tag}
+	if strict {
+		typ := section.valueType()
+		if node.Kind == yaml.MappingNode {
+			typ = section.objectType()
+		}
+		if errs := checkKnownFields(&node, typ); len(errs) > 0 {
+			return target, errs
+		}
+	}
+	err := section.decode(&node)
 	target = append(target, section)
-	return target, err
+	if err != nil {
+		return target, []error{withYamlPosition(err)}
+	}
+	return target, nil
 }
 
 func commandFromString(cmd string) (Command, error) {
@@ -85,3 +153,95 @@ func commandFromString(cmd string) (Command, error) {
 		Args: command[1:],
 	}, nil
 }
+
+// checkRootTags checks that all root tags are known: `settings`, actions or declared variables namespaces.
+func checkRootTags(root *yaml.Node, namespaces []string) []error {
+	var (
+		errs    []error
+		allowed = entity.SliceSet(append([]string{DefaultVarsNamespace}, namespaces...))
+	)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key := root.Content[i]
+		if _, ok := allowed[key.Value]; ok || key.Value == SettingsHTTP || IsActionTag(key.Value) {
+			continue
+		}
+		errs = append(errs, newSourceError(nodePosition(key), xerrors.Errorf("unknown root tag [%s]", key.Value)))
+	}
+	return errs
+}
+
+// checkKnownFields checks that all mapping keys of the node are declared as `yaml` fields of the type.
+func checkKnownFields(node *yaml.Node, typ reflect.Type) []error {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	var errs []error
+	switch typ.Kind() {
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return nil
+		}
+		for _, item := range node.Content {
+			errs = append(errs, checkKnownFields(item, typ.Elem())...)
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		for i := 1; i < len(node.Content); i += 2 {
+			errs = append(errs, checkKnownFields(node.Content[i], typ.Elem())...)
+		}
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		fields := make(map[string]reflect.Type, typ.NumField())
+		yamlFields(typ, fields)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, val := node.Content[i], node.Content[i+1]
+			fieldType, ok := fields[key.Value]
+			if !ok {
+				errs = append(errs, newSourceError(nodePosition(key), xerrors.Errorf("field [%s] not found in type %s", key.Value, typ)))
+				continue
+			}
+			errs = append(errs, checkKnownFields(val, fieldType)...)
+		}
+	}
+	return errs
+}
+
+func yamlFields(typ reflect.Type, fields map[string]reflect.Type) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.IsExported() && strings.Contains(field.Tag.Get("yaml"), ",inline") {
+			inlineType := field.Type
+			for inlineType.Kind() == reflect.Pointer {
+				inlineType = inlineType.Elem()
+			}
+			if inlineType.Kind() == reflect.Struct {
+				yamlFields(inlineType, fields)
+			}
+			continue
+		}
+		if name, ok := yamlFieldName(field); ok {
+			fields[name] = field.Type
+		}
+	}
+}
+
+// yamlFieldName returns the `yaml` name of the field, false if the field is not serialized.
+func yamlFieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return entity.Empty, false
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), entity.Comma)
+	switch name {
+	case "-":
+		return entity.Empty, false
+	case entity.Empty:
+		return strings.ToLower(field.Name), true
+	default:
+		return name, true
+	}
+}
@@ -61,7 +61,7 @@ settings:
 			queryVal,
 		)
 
-		conf, err := NewYamlConfigUnmarshaler().Unmarshal([]byte(in))
+		conf, err := NewYamlConfigUnmarshaler(false).Unmarshal([]byte(in))
 		assert.NoError(t, err)
 		assert.NotNil(t, conf.Settings.HTTP)
 
@@ -106,7 +106,7 @@ settings:
 			actionB,
 		)
 
-		conf, err := NewYamlConfigUnmarshaler().Unmarshal([]byte(in))
+		conf, err := NewYamlConfigUnmarshaler(false).Unmarshal([]byte(in))
 		assert.NoError(t, err)
 
 		assert.NotEmpty(t, conf.Settings.Groups)
@@ -116,4 +116,271 @@ settings:
 		assert.Equal(t, conf.Settings.Groups[1], Group{Name: groupB, Actions: []string{actionA, actionB}, Manual: true})
 
 	})
+
+	t.Run("template_skip_ext", func(t *testing.T) {
+		in := `
+settings:
+  template:
+    skip_ext: [png, .svg]
+files:
+  - path: a.txt
+    data: "{{ .v }}"
+    template: false
+  - path: b.txt
+    data: "{{ .v }}"
+`
+		conf, err := NewYamlConfigUnmarshaler(false).Unmarshal([]byte(in))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"png", ".svg"}, conf.Settings.Template.SkipExt)
+
+		actions, err := conf.FilesActions(NewExpander(nil, nil, nil))
+		assert.NoError(t, err)
+		assert.Len(t, actions, 1)
+		assert.Len(t, actions[0].Val, 2)
+		assert.True(t, actions[0].Val[0].Raw)
+		assert.False(t, actions[0].Val[1].Raw)
+	})
+}
+
+func Test_YamlUnmarshaler_strict(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success_when_root_tags_are_known", func(t *testing.T) {
+		const (
+			in = `
+settings:
+  namespaces: [matrix]
+vars:
+  name: some
+matrix:
+  version: 1.19
+dirs1:
+  - some/dir
+cmd:
+  - exec: ls
+    dir: .
+`
+		)
+		conf, err := NewYamlConfigUnmarshaler(true).Unmarshal([]byte(in))
+		assert.NoError(t, err)
+		assert.Len(t, conf.Dirs, 1)
+		assert.Len(t, conf.Cmd, 1)
+	})
+	t.Run("success_ignore_unknown_root_tag_when_not_strict", func(t *testing.T) {
+		const (
+			in = `
+file:
+  - path: some/file
+`
+		)
+		_, err := NewYamlConfigUnmarshaler(false).Unmarshal([]byte(in))
+		assert.NoError(t, err)
+	})
+	t.Run("error_when_unknown_root_tag", func(t *testing.T) {
+		const (
+			in = `
+dirs:
+  - some/dir
+cdm2:
+  - ls
+`
+		)
+		_, err := NewYamlConfigUnmarshaler(true).Unmarshal([]byte(in))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "line 4, column 1: unknown root tag [cdm2]")
+	})
+	t.Run("error_when_unknown_root_tag_and_strict_set_in_settings", func(t *testing.T) {
+		const (
+			in = `
+settings:
+  strict: true
+file:
+  - path: some/file
+`
+		)
+		_, err := NewYamlConfigUnmarshaler(false).Unmarshal([]byte(in))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "line 4, column 1: unknown root tag [file]")
+	})
+	t.Run("error_when_unknown_file_field", func(t *testing.T) {
+		const (
+			in = `
+files:
+  - path: some/file
+    dta: some data
+`
+		)
+		_, err := NewYamlConfigUnmarshaler(true).Unmarshal([]byte(in))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "line 4, column 5: field [dta] not found in type config.File")
+	})
+	t.Run("error_when_unknown_command_field", func(t *testing.T) {
+		const (
+			in = `
+cmd:
+  - ls -a
+  - exec: ls
+    arg: [-a]
+`
+		)
+		_, err := NewYamlConfigUnmarshaler(true).Unmarshal([]byte(in))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "line 5, column 5: field [arg] not found in type config.Command")
+	})
+	t.Run("error_when_unknown_group_field", func(t *testing.T) {
+		const (
+			in = `
+settings:
+  groups:
+    - name: some
+      action: [cmd]
+cmd:
+  - ls
+`
+		)
+		_, err := NewYamlConfigUnmarshaler(true).Unmarshal([]byte(in))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "line 5, column 7: field [action] not found in type config.Group")
+	})
+}
+
+func Test_YamlUnmarshaler_UnmarshalAll(t *testing.T) {
+	t.Parallel()
+
+	const (
+		in = `
+file:
+  - path: some/file
+files:
+  - path: some/file
+    dta: some data
+cmd:
+  - exec: ls
+    arg: [-a]
+`
+	)
+
+	t.Run("return_all_errors", func(t *testing.T) {
+		_, errs := NewYamlConfigUnmarshaler(true).UnmarshalAll([]byte(in))
+		assert.Len(t, errs, 3)
+		assert.Contains(t, errs[0].Error(), "line 2, column 1: unknown root tag [file]")
+		assert.Contains(t, errs[1].Error(), "line 6, column 5: field [dta] not found in type config.File")
+		assert.Contains(t, errs[2].Error(), "line 9, column 5: field [arg] not found in type config.Command")
+	})
+	t.Run("unmarshal_return_first_error", func(t *testing.T) {
+		_, err := NewYamlConfigUnmarshaler(true).Unmarshal([]byte(in))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown root tag [file]")
+	})
+}
+
+func Test_YamlUnmarshaler_section_object(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success_decode_object_form", func(t *testing.T) {
+		const (
+			in = `
+dirs:
+  - a
+files_db:
+  when: .vars.db
+  items:
+    - path: db.sql
+      data: some
+`
+		)
+		conf, err := NewYamlConfigUnmarshaler(true).Unmarshal([]byte(in))
+		assert.NoError(t, err)
+		assert.Equal(t, entity.Empty, conf.Dirs[0].When)
+		assert.Equal(t, ".vars.db", conf.Files[0].When)
+		assert.Equal(t, "db.sql", conf.Files[0].Val[0].Path)
+
+		actions, err := conf.FilesActions(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, ".vars.db", actions[0].When)
+		assert.Equal(t, 5, actions[0].Priority)
+	})
+	t.Run("success_decode_entries_conditions", func(t *testing.T) {
+		const (
+			in = `
+dirs:
+  - api
+  - path: docker
+    when: .vars.docker
+files:
+  - path: Dockerfile
+    data: FROM scratch
+    when: .vars.docker
+cmd:
+  - ls
+  - exec: docker
+    args: [build, .]
+    when: .vars.docker
+`
+		)
+		conf, err := NewYamlConfigUnmarshaler(true).Unmarshal([]byte(in))
+		assert.NoError(t, err)
+		dirs, err := conf.DirActions(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.Dir{{Path: "api"}, {Path: "docker", When: ".vars.docker"}}, dirs[0].Val)
+
+		files, err := conf.FilesActions(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, ".vars.docker", files[0].Val[0].When)
+
+		cmds, err := conf.CommandActions(nil)
+		assert.NoError(t, err)
+		cmd := cmds[0].Val
+		assert.Equal(t, entity.Empty, cmd[0].When)
+		assert.Equal(t, ".vars.docker", cmd[1].When)
+	})
+	t.Run("success_decode_fs_entries", func(t *testing.T) {
+		const (
+			in = `
+fs:
+  - api
+  - path: web
+    exclude: [node_modules]
+    include: ["**/*.ts"]
+`
+		)
+		conf, err := NewYamlConfigUnmarshaler(true).Unmarshal([]byte(in))
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.FsDir{
+			{Path: "api"},
+			{Path: "web", Include: []string{"**/*.ts"}, Exclude: []string{"node_modules"}},
+		}, conf.FsActions()[0].Val)
+	})
+	t.Run("success_decode_depends_on", func(t *testing.T) {
+		const (
+			in = `
+dirs:
+  - a
+cmd:
+  depends_on: [dirs, files]
+  parallel: true
+  items:
+    - ls
+`
+		)
+		conf, err := NewYamlConfigUnmarshaler(true).Unmarshal([]byte(in))
+		assert.NoError(t, err)
+
+		actions, err := conf.CommandActions(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"dirs", "files"}, actions[0].DependsOn)
+		assert.True(t, actions[0].Parallel)
+	})
+	t.Run("error_when_unknown_object_field", func(t *testing.T) {
+		const (
+			in = `
+dirs:
+  if: .vars.db
+  items: [a]
+`
+		)
+		_, err := NewYamlConfigUnmarshaler(true).Unmarshal([]byte(in))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "line 3, column 3: field [if] not found in type config.sectionObject")
+	})
 }
@@ -11,8 +11,21 @@ import (
 type (
 	TemplateOptionsKey string
 	MissingKeyValue    string
+	ConfigFormat       string
+	OnExists           string
 )
 
+func (f ConfigFormat) Valid() error {
+	switch f {
+	case ConfigFormatYAML,
+		ConfigFormatJSON,
+		ConfigFormatTOML:
+		return nil
+	default:
+		return xerrors.Errorf("config format is not valid: %v", f)
+	}
+}
+
 func (v MissingKeyValue) Valid() error {
 	switch v {
 	case MissingKeyDefault,
@@ -25,6 +38,23 @@ func (v MissingKeyValue) Valid() error {
 	}
 }
 
+// Valid returns the error when the policy of the existing file is unknown, the empty policy is valid.
+func (v OnExists) Valid() error {
+	switch v {
+	case Empty,
+		OnExistsOverwrite,
+		OnExistsSkip,
+		OnExistsError,
+		OnExistsBackup,
+		OnExistsAppend,
+		OnExistsPrepend,
+		OnExistsPrompt:
+		return nil
+	default:
+		return xerrors.Errorf("`on_exists` policy is not valid: %v", v)
+	}
+}
+
 //goland:noinspection SpellCheckingInspection
 const (
 	TemplateOptionsMissingKey TemplateOptionsKey = "missingkey"
@@ -34,6 +64,18 @@ const (
 	MissingKeyZero    MissingKeyValue = "zero"
 	MissingKeyError   MissingKeyValue = "error"
 
+	ConfigFormatYAML ConfigFormat = "yaml"
+	ConfigFormatJSON ConfigFormat = "json"
+	ConfigFormatTOML ConfigFormat = "toml"
+
+	OnExistsOverwrite OnExists = "overwrite"
+	OnExistsSkip      OnExists = "skip"
+	OnExistsError     OnExists = "error"
+	OnExistsBackup    OnExists = "backup"
+	OnExistsAppend    OnExists = "append"
+	OnExistsPrepend   OnExists = "prepend"
+	OnExistsPrompt    OnExists = "prompt"
+
 	Space      = " "
 	Empty      = ""
 	Dash       = "-"
@@ -66,6 +108,10 @@ type (
 		Apply(path string) error
 	}
 
+	LinkStrategy interface {
+		Apply(link Link) (Link, error)
+	}
+
 	TemplateProc interface {
 		Process(name, text string) (string, error)
 	}
@@ -98,9 +144,13 @@ type (
 )
 
 type ExecutorBuilder struct {
-	Action   string
-	Priority int
-	ProcFn   func() (Executor, error)
+	Action    string
+	Priority  int
+	DependsOn []string
+	Parallel  bool
+	// Skipped is true when the action is skipped by filters or conditions (ProcFn is nil).
+	Skipped bool
+	ProcFn  func(logger Logger) (Executor, error)
 }
 
 type Group struct {
@@ -112,7 +162,16 @@ type Group struct {
 type Action[T any] struct {
 	Priority int
 	Name     string
-	Val      T
+	// When is the template expression (`.vars.db`), the action is skipped when the result is false.
+	When string
+	// DependsOn contains names of the actions which must be executed before the action.
+	DependsOn []string
+	// Parallel is true when the action can be executed concurrently with other parallel actions (`-jobs` flag).
+	Parallel bool
+	// Before and After are the commands executed before and after the action.
+	Before []Command
+	After  []Command
+	Val    T
 }
 
 func (a Action[T]) WithPriority(priority int) Action[T] {
@@ -123,28 +182,97 @@ func (a Action[T]) WithPriority(priority int) Action[T] {
 type TargetFs struct {
 	TargetDir string
 	Fs        fs.FS
+	// Raw is true when the files are saved as is, the paths of the files are still templates.
+	Raw bool
 }
 
 type UndefinedFile struct {
-	Path  string
-	Data  *[]byte
-	Get   *HTTPClientParams
-	Local *string
+	Path     string
+	Data     *[]byte
+	Get      *HTTPClientParams
+	Local    *string
+	When     string
+	OnExists OnExists
+	Mode     fs.FileMode
+	// Raw is true when the data is saved as is (without templating).
+	Raw bool
 }
 
+type Dir struct {
+	Path string
+	When string
+	Mode fs.FileMode
+}
+
+// Link is the symbolic link (or the hard link) at the path to the target.
+type Link struct {
+	Path     string
+	Target   string
+	Hard     bool
+	When     string
+	OnExists OnExists
+}
+
+// FsDir is the dir processed by the `fs` action, the processed paths are selected by the patterns.
+type FsDir struct {
+	Path    string
+	Include []string
+	Exclude []string
+}
+
+// Copy is the local dir tree, which is copied to the destination dir.
+type Copy struct {
+	Src     string
+	Dst     string
+	Include []string
+	Exclude []string
+	// Template is true when the files data is processed as the template.
+	Template bool
+	When     string
+}
+
+// FileModes are the default permissions of the created files and dirs.
+// The zero mode keeps the default permissions (`os.ModePerm` masked by umask).
+type FileModes struct {
+	File fs.FileMode
+	Dir  fs.FileMode
+}
+
+// Sources of the files data.
+const (
+	FileSourceData  = "data"
+	FileSourceLocal = "local"
+	FileSourceGet   = "get"
+	FileSourceFs    = "fs"
+)
+
 type DataFile struct {
 	FileInfo
 	Data []byte
+	// Source is the source of the data (`data`, `local`, `get`, `fs`).
+	Source string
+	// OnExists is the policy of the existing file, the default policy is used when it is empty.
+	OnExists OnExists
+	// Mode is the permissions of the file, the default mode is used when it is zero.
+	Mode fs.FileMode
+	// Raw is true when the data is saved as is (without templating).
+	Raw bool
 }
 
 type LocalFile struct {
 	FileInfo
 	LocalPath string
+	OnExists  OnExists
+	Mode      fs.FileMode
+	Raw       bool
 }
 
 type RemoteFile struct {
 	FileInfo
 	HTTPClientParams
+	OnExists OnExists
+	Mode     fs.FileMode
+	Raw      bool
 }
 
 type FileInfo struct {
@@ -186,6 +314,14 @@ type Command struct {
 	Cmd  string
 	Args []string
 	Dir  string
+	When string
+}
+
+// Hooks are the commands executed before and after all actions and when the execution fails.
+type Hooks struct {
+	BeforeAll []Command
+	AfterAll  []Command
+	OnFailure []Command
 }
 
 type RegexpChain struct {
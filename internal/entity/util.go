@@ -1,6 +1,9 @@
 package entity
 
-import "reflect"
+import (
+	"reflect"
+	"strings"
+)
 
 func MergeKeys(dst, src map[string]any) map[string]any {
 	if dst == nil {
@@ -35,3 +38,55 @@ func NotNilValues(values ...any) int {
 	}
 	return counter
 }
+
+// VarPath splits the dotted variables tree path (`.vars.name`) to the keys.
+func VarPath(path string) []string {
+	var keys []string
+	for _, key := range strings.Split(path, Dot) {
+		if key = strings.TrimSpace(key); key != Empty {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// SetVar sets the value to the variables tree by the dotted path and returns the tree.
+func SetVar(vars map[string]any, path string, val any) map[string]any {
+	if vars == nil {
+		vars = make(map[string]any)
+	}
+	keys := VarPath(path)
+	if len(keys) == 0 {
+		return vars
+	}
+	current := vars
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := current[key].(map[string]any)
+		if !ok {
+			next = make(map[string]any, 1)
+			current[key] = next
+		}
+		current = next
+	}
+	current[keys[len(keys)-1]] = val
+	return vars
+}
+
+// LookupVar returns the value of the variables tree by the dotted path.
+func LookupVar(vars map[string]any, path string) (any, bool) {
+	keys := VarPath(path)
+	if len(keys) == 0 {
+		return nil, false
+	}
+	var current any = vars
+	for _, key := range keys {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
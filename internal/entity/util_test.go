@@ -117,3 +117,30 @@ func Test_NotNilValues(t *testing.T) {
 		})
 	}
 }
+
+func Test_SetVar_LookupVar(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success_set_and_lookup", func(t *testing.T) {
+		vars := map[string]any{"vars": map[string]any{"a": "a"}}
+		vars = SetVar(vars, ".vars.b.c", 1)
+		assert.Equal(t, map[string]any{"vars": map[string]any{"a": "a", "b": map[string]any{"c": 1}}}, vars)
+
+		val, ok := LookupVar(vars, ".vars.b.c")
+		assert.True(t, ok)
+		assert.Equal(t, 1, val)
+	})
+	t.Run("success_set_replaces_not_map_value", func(t *testing.T) {
+		vars := SetVar(map[string]any{"vars": "a"}, "vars.b", true)
+		assert.Equal(t, map[string]any{"vars": map[string]any{"b": true}}, vars)
+	})
+	t.Run("lookup_not_found", func(t *testing.T) {
+		vars := map[string]any{"vars": map[string]any{"a": "a"}}
+		_, ok := LookupVar(vars, ".vars.b")
+		assert.False(t, ok)
+		_, ok = LookupVar(vars, ".vars.a.b")
+		assert.False(t, ok)
+		_, ok = LookupVar(vars, Dot)
+		assert.False(t, ok)
+	})
+}
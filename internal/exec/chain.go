@@ -1,6 +1,9 @@
 package exec
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"sync"
 
 	"golang.org/x/xerrors"
@@ -26,26 +29,137 @@ func (c *Chain) Exec() error {
 	return nil
 }
 
+// Hooks are the executors of the whole execution hooks (can be nil).
+type Hooks struct {
+	BeforeAll entity.Executor
+	AfterAll  entity.Executor
+	// OnFailure creates the failure hook's executor by the name of the failed action (empty when the failure is not
+	// the action's failure) and the error.
+	OnFailure func(action string, err error) (entity.Executor, error)
+}
+
 type PreprocessingChain struct {
 	preprocessors *Preprocessors
+	hooks         Hooks
 	chain         *Chain
 }
 
-func NewPreprocessingChain(preprocessors *Preprocessors, executors []entity.Executor) *PreprocessingChain {
+func NewPreprocessingChain(preprocessors *Preprocessors, hooks Hooks, executors []entity.Executor) *PreprocessingChain {
 	return &PreprocessingChain{
 		preprocessors: preprocessors,
+		hooks:         hooks,
 		chain:         NewChain(executors),
 	}
 }
 
 func (c *PreprocessingChain) Exec() error {
+	err := c.exec()
+	if err != nil {
+		return c.onFailure(err)
+	}
+	return nil
+}
+
+func (c *PreprocessingChain) exec() error {
+	if c.hooks.BeforeAll != nil {
+		if err := c.hooks.BeforeAll.Exec(); err != nil {
+			return xerrors.Errorf("before all hook: %w", err)
+		}
+	}
 	for i, preprocessor := range c.preprocessors.Get() {
 		err := preprocessor.Process()
 		if err != nil {
 			return xerrors.Errorf("preprocess [%d]: %w", i, err)
 		}
 	}
-	return c.chain.Exec()
+	if err := c.chain.Exec(); err != nil {
+		return err
+	}
+	if c.hooks.AfterAll != nil {
+		if err := c.hooks.AfterAll.Exec(); err != nil {
+			return xerrors.Errorf("after all hook: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *PreprocessingChain) onFailure(err error) error {
+	if c.hooks.OnFailure == nil {
+		return err
+	}
+
+	var (
+		action    string
+		cause     = err
+		actionErr *ActionError
+	)
+	if errors.As(err, &actionErr) {
+		action, cause = actionErr.Action, actionErr.Err
+	}
+
+	hook, hookErr := c.hooks.OnFailure(action, cause)
+	if hookErr == nil && hook != nil {
+		hookErr = hook.Exec()
+	}
+	if hookErr != nil {
+		return xerrors.Errorf("on failure hook [%v]: %w", hookErr, err)
+	}
+	return err
+}
+
+// ActionError is the failure of the action's execution.
+type ActionError struct {
+	Action string
+	Err    error
+}
+
+func (e *ActionError) Error() string {
+	return fmt.Sprintf("action [%s]: %v", e.Action, e.Err)
+}
+
+func (e *ActionError) Unwrap() error {
+	return e.Err
+}
+
+// ActionExecutor executes the action's executor between the `before` and `after` hooks (can be nil).
+// Failures are returned as [ActionError].
+type ActionExecutor struct {
+	action   string
+	executor entity.Executor
+	before   entity.Executor
+	after    entity.Executor
+}
+
+func NewActionExecutor(action string, executor, before, after entity.Executor) *ActionExecutor {
+	return &ActionExecutor{
+		action:   action,
+		executor: executor,
+		before:   before,
+		after:    after,
+	}
+}
+
+func (e *ActionExecutor) Exec() error {
+	return e.ExecContext(context.Background())
+}
+
+func (e *ActionExecutor) ExecContext(ctx context.Context) error {
+	if e.before != nil {
+		if err := execContext(ctx, e.before); err != nil {
+			return &ActionError{Action: e.action, Err: xerrors.Errorf("before hook: %w", err)}
+		}
+	}
+	if e.executor != nil {
+		if err := execContext(ctx, e.executor); err != nil {
+			return &ActionError{Action: e.action, Err: err}
+		}
+	}
+	if e.after != nil {
+		if err := execContext(ctx, e.after); err != nil {
+			return &ActionError{Action: e.action, Err: xerrors.Errorf("after hook: %w", err)}
+		}
+	}
+	return nil
 }
 
 type Preprocessors struct {
@@ -0,0 +1,88 @@
+package exec
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+type mockFnExecutor func() error
+
+func (m mockFnExecutor) Exec() error {
+	return m()
+}
+
+func Test_PreprocessingChain_hooks(t *testing.T) {
+	t.Parallel()
+
+	newRecorder := func(calls *[]string, name string, err error) entity.Executor {
+		return mockFnExecutor(func() error {
+			*calls = append(*calls, name)
+			return err
+		})
+	}
+
+	t.Run("success_execute_hooks_in_order", func(t *testing.T) {
+		var calls []string
+		hooks := Hooks{
+			BeforeAll: newRecorder(&calls, "before_all", nil),
+			AfterAll:  newRecorder(&calls, "after_all", nil),
+			OnFailure: func(_ string, _ error) (entity.Executor, error) {
+				return newRecorder(&calls, "on_failure", nil), nil
+			},
+		}
+		action := NewActionExecutor("cmd",
+			newRecorder(&calls, "cmd", nil),
+			newRecorder(&calls, "before", nil),
+			newRecorder(&calls, "after", nil),
+		)
+
+		err := NewPreprocessingChain(nil, hooks, []entity.Executor{action}).Exec()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"before_all", "before", "cmd", "after", "after_all"}, calls)
+	})
+	t.Run("error_execute_on_failure_with_failed_action", func(t *testing.T) {
+		var (
+			calls  []string
+			action string
+			cause  error
+		)
+		hooks := Hooks{
+			AfterAll: newRecorder(&calls, "after_all", nil),
+			OnFailure: func(a string, err error) (entity.Executor, error) {
+				action, cause = a, err
+				return newRecorder(&calls, "on_failure", nil), nil
+			},
+		}
+		executors := []entity.Executor{
+			NewActionExecutor("dirs", newRecorder(&calls, "dirs", nil), nil, nil),
+			NewActionExecutor("cmd", newRecorder(&calls, "cmd", fmt.Errorf("some error")), nil, nil),
+			NewActionExecutor("files", newRecorder(&calls, "files", nil), nil, nil),
+		}
+
+		err := NewPreprocessingChain(nil, hooks, executors).Exec()
+		assert.Error(t, err)
+		assert.Equal(t, "execute proc [1]: action [cmd]: some error", err.Error())
+		assert.Equal(t, []string{"dirs", "cmd", "on_failure"}, calls)
+		assert.Equal(t, "cmd", action)
+		assert.Equal(t, "some error", cause.Error())
+	})
+	t.Run("error_when_on_failure_hook_fails", func(t *testing.T) {
+		var calls []string
+		hooks := Hooks{
+			BeforeAll: newRecorder(&calls, "before_all", fmt.Errorf("before error")),
+			OnFailure: func(action string, _ error) (entity.Executor, error) {
+				assert.Equal(t, entity.Empty, action)
+				return newRecorder(&calls, "on_failure", fmt.Errorf("hook error")), nil
+			},
+		}
+
+		err := NewPreprocessingChain(nil, hooks, nil).Exec()
+		assert.Error(t, err)
+		assert.Equal(t, "on failure hook [hook error]: before all hook: before error", err.Error())
+		assert.Equal(t, []string{"before_all", "on_failure"}, calls)
+	})
+}
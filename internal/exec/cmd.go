@@ -2,15 +2,21 @@ package exec
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os/exec"
 	"strings"
+	"time"
 
 	"golang.org/x/xerrors"
 
 	"github.com/kozmod/progen/internal/entity"
 )
 
+const (
+	cancelWaitDelay = 100 * time.Millisecond
+)
+
 type CommandExecutor struct {
 	commands []entity.Command
 	logger   entity.Logger
@@ -24,9 +30,16 @@ func NewCommandExecutor(commands []entity.Command, logger entity.Logger) *Comman
 }
 
 func (p *CommandExecutor) Exec() error {
+	return p.ExecContext(context.Background())
+}
+
+// ExecContext executes the commands, the running command is killed when the context is done.
+func (p *CommandExecutor) ExecContext(ctx context.Context) error {
 	for _, command := range p.commands {
 		dir := command.Dir
-		cmd := exec.Command(command.Cmd, command.Args...)
+		cmd := exec.CommandContext(ctx, command.Cmd, command.Args...)
+		// the output of the killed command's children is not waited
+		cmd.WaitDelay = cancelWaitDelay
 		var (
 			stdout bytes.Buffer
 			stderr bytes.Buffer
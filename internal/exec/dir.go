@@ -35,25 +35,62 @@ func (p *DirExecutor) Exec() error {
 
 type MkdirAllStrategy struct {
 	fileMode os.FileMode
-	logger   entity.Logger
+	// modes contains the permissions of the dirs, which override the default mode
+	modes   map[string]os.FileMode
+	journal *Journal
+	logger  entity.Logger
 }
 
-func NewMkdirAllStrategy(logger entity.Logger) *MkdirAllStrategy {
+func NewMkdirAllStrategy(fileMode os.FileMode, modes map[string]os.FileMode, journal *Journal, logger entity.Logger) *MkdirAllStrategy {
 	return &MkdirAllStrategy{
-		fileMode: os.ModePerm,
+		fileMode: fileMode,
+		modes:    modes,
+		journal:  journal,
 		logger:   logger,
 	}
 }
 
 func (p *MkdirAllStrategy) Apply(dir string) (string, error) {
-	err := os.MkdirAll(dir, p.fileMode)
+	err := p.journal.Mkdir(dir)
+	if err != nil {
+		return entity.Empty, xerrors.Errorf("create dir [%s]: %w", dir, err)
+	}
+	err = mkdirAll(dir, p.fileMode)
 	if err != nil {
 		return entity.Empty, xerrors.Errorf("create dir [%s]: %w", dir, err)
 	}
+	if mode, ok := p.modes[dir]; ok && mode != 0 {
+		// the mode of the dir is applied to the existing dir too
+		if err = os.Chmod(dir, mode); err != nil {
+			return entity.Empty, xerrors.Errorf("create dir [%s]: change mode: %w", dir, err)
+		}
+	}
 	p.logger.Infof("dir created: %s", dir)
 	return dir, nil
 }
 
+// mkdirAll creates the dir with the parents. The created dirs get the exact mode (not masked by umask),
+// the zero mode creates the dirs as [os.MkdirAll] with [os.ModePerm].
+func mkdirAll(dir string, mode os.FileMode) error {
+	if mode == 0 {
+		return os.MkdirAll(dir, os.ModePerm)
+	}
+	created, err := notExistingDirs(dir)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(dir, mode); err != nil {
+		return err
+	}
+	// the nested dirs are changed before the parents (the parent's mode can deny the access)
+	for _, path := range created {
+		if err = os.Chmod(path, mode); err != nil {
+			return xerrors.Errorf("change mode [%s]: %w", path, err)
+		}
+	}
+	return nil
+}
+
 type DryRunMkdirAllStrategy struct {
 	fileMode os.FileMode
 	logger   entity.Logger
@@ -1,6 +1,7 @@
 package exec
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -14,20 +15,44 @@ func Test_MkdirAllStrategy(t *testing.T) {
 		someDir = "some_dir"
 	)
 
-	WithTempDir(t, func(tmpDir string) {
-		var (
-			exp        = filepath.Join(tmpDir, someDir)
-			mockLogger = MockLogger{
-				infof: func(format string, args ...any) {
-					assert.NotEmpty(t, format)
-					assert.ElementsMatch(t, []string{exp}, args)
-				},
-			}
-		)
-
-		res, err := NewMkdirAllStrategy(mockLogger).Apply(exp)
-		assert.NoError(t, err)
-		assert.Equal(t, exp, res)
-		assert.DirExists(t, res)
+	t.Run("success", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				exp        = filepath.Join(tmpDir, someDir)
+				mockLogger = MockLogger{
+					infof: func(format string, args ...any) {
+						assert.NotEmpty(t, format)
+						assert.ElementsMatch(t, []string{exp}, args)
+					},
+				}
+			)
+
+			res, err := NewMkdirAllStrategy(0, nil, nil, mockLogger).Apply(exp)
+			assert.NoError(t, err)
+			assert.Equal(t, exp, res)
+			assert.DirExists(t, res)
+		})
+	})
+	t.Run("success_with_modes", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a          = assert.New(t)
+				parent     = filepath.Join(tmpDir, someDir)
+				exp        = filepath.Join(parent, "private")
+				mockLogger = MockLogger{
+					infof: func(format string, args ...any) {},
+				}
+			)
+
+			_, err := NewMkdirAllStrategy(0o750, map[string]os.FileMode{exp: 0o700}, nil, mockLogger).Apply(exp)
+			a.NoError(err)
+
+			info, err := os.Stat(parent)
+			a.NoError(err)
+			a.Equal(os.FileMode(0o750), info.Mode().Perm())
+			info, err = os.Stat(exp)
+			a.NoError(err)
+			a.Equal(os.FileMode(0o700), info.Mode().Perm())
+		})
 	})
 }
@@ -0,0 +1,79 @@
+package exec
+
+import (
+	"slices"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+// backupFileExt is the extension of the existing file copy (`backup` policy).
+const backupFileExt = ".orig"
+
+// OnExistsPolicy resolves the policy of the existing files. The policy of the file overrides the default policy,
+// the `prompt` policy is resolved by the prompt function. Methods of the nil OnExistsPolicy use `overwrite` policy.
+type OnExistsPolicy struct {
+	mx       sync.Mutex
+	def      entity.OnExists
+	promptFn func(path string) (entity.OnExists, error)
+}
+
+// NewOnExistsPolicy creates the [OnExistsPolicy], the `prompt` policy is resolved as `skip` when `promptFn` is nil.
+func NewOnExistsPolicy(def entity.OnExists, promptFn func(path string) (entity.OnExists, error)) *OnExistsPolicy {
+	return &OnExistsPolicy{
+		def:      def,
+		promptFn: promptFn,
+	}
+}
+
+// Resolve returns the policy of the existing path, the policy of the path overrides the default policy.
+func (p *OnExistsPolicy) Resolve(path string, policy entity.OnExists) (entity.OnExists, error) {
+	if policy == entity.Empty && p != nil {
+		policy = p.def
+	}
+	if err := policy.Valid(); err != nil {
+		return entity.Empty, xerrors.Errorf("on exists: %w", err)
+	}
+	switch {
+	case policy == entity.Empty:
+		return entity.OnExistsOverwrite, nil
+	case policy != entity.OnExistsPrompt:
+		return policy, nil
+	case p == nil || p.promptFn == nil:
+		return entity.OnExistsSkip, nil
+	}
+
+	// the answers are asked one by one when the actions are executed in parallel
+	p.mx.Lock()
+	defer p.mx.Unlock()
+	answer, err := p.promptFn(path)
+	if err != nil {
+		return entity.Empty, xerrors.Errorf("on exists: prompt [%s]: %w", path, err)
+	}
+	if !slices.Contains(OnExistsPromptOptions, string(answer)) {
+		return entity.Empty, xerrors.Errorf("on exists: prompt [%s]: answer is not a policy: %v", path, answer)
+	}
+	return answer, nil
+}
+
+// OnExistsPromptOptions are the policies, which can be selected by the prompt.
+var OnExistsPromptOptions = []string{
+	string(entity.OnExistsSkip),
+	string(entity.OnExistsOverwrite),
+	string(entity.OnExistsBackup),
+	string(entity.OnExistsAppend),
+	string(entity.OnExistsPrepend),
+	string(entity.OnExistsError),
+}
+
+// joinData joins the data of the files, the new line is added between the data when it is missing.
+func joinData(first, second []byte) []byte {
+	res := make([]byte, 0, len(first)+len(second)+1)
+	res = append(res, first...)
+	if len(first) > 0 && first[len(first)-1] != '\n' {
+		res = append(res, '\n')
+	}
+	return append(res, second...)
+}
@@ -1,11 +1,14 @@
 package exec
 
 import (
+	"bytes"
 	"context"
 	"net/http"
 	"os"
 	"sort"
+	"strings"
 	"sync"
+	"unicode/utf8"
 
 	resty "github.com/go-resty/resty/v2"
 	"golang.org/x/sync/errgroup"
@@ -43,19 +46,32 @@ func (e *FilesExecutor) Exec() error {
 	return nil
 }
 
+// binarySniffLen is the length of the data prefix, which is checked to detect the binary data.
+const binarySniffLen = 8000
+
+// TemplateFileStrategy processes the file's data as the template. The raw files (the binary data,
+// the files with the skipped extensions) are saved as is.
 type TemplateFileStrategy struct {
 	templateProcFn func() entity.TemplateProc
+	skipExt        []string
 }
 
-func NewTemplateFileStrategy(templateData, templateFns map[string]any, templateOptions []string) *TemplateFileStrategy {
+func NewTemplateFileStrategy(templateData, templateFns map[string]any, templateOptions, skipExt []string) *TemplateFileStrategy {
 	return &TemplateFileStrategy{
 		templateProcFn: func() entity.TemplateProc {
 			return entity.NewTemplateProc(templateData, templateFns, templateOptions)
 		},
+		skipExt: normalizeExt(skipExt),
 	}
 }
 
 func (p *TemplateFileStrategy) Apply(file entity.DataFile) (entity.DataFile, error) {
+	if !file.Raw {
+		file.Raw = hasExt(file.Name(), p.skipExt) || isBinary(file.Data)
+	}
+	if file.Raw {
+		return file, nil
+	}
 	filePath := file.Path()
 
 	data, err := p.templateProcFn().Process(filePath, string(file.Data))
@@ -66,36 +82,176 @@ func (p *TemplateFileStrategy) Apply(file entity.DataFile) (entity.DataFile, err
 	return file, nil
 }
 
+// normalizeExt returns the lower case extensions with the leading dot: `PNG` -> `.png`.
+func normalizeExt(extensions []string) []string {
+	res := make([]string, 0, len(extensions))
+	for _, ext := range extensions {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == entity.Empty {
+			continue
+		}
+		if !strings.HasPrefix(ext, entity.Dot) {
+			ext = entity.Dot + ext
+		}
+		res = append(res, ext)
+	}
+	return res
+}
+
+// hasExt reports whether the file name ends with any extension (the extensions are normalized).
+func hasExt(name string, extensions []string) bool {
+	name = strings.ToLower(name)
+	for _, ext := range extensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBinary reports whether the data is binary: it contains the NUL byte or the sniffed MIME type is not a text
+// and the data is not valid UTF-8 (the text can start with the binary signature, e.g. `BM` or `GIF8`).
+func isBinary(data []byte) bool {
+	sniff := data[:min(len(data), binarySniffLen)]
+	if bytes.IndexByte(sniff, 0) >= 0 {
+		return true
+	}
+	return !strings.HasPrefix(http.DetectContentType(sniff), "text/") && !validUTF8Prefix(sniff)
+}
+
+// validUTF8Prefix reports whether the data is valid UTF-8, the incomplete rune at the end of the data is ignored.
+func validUTF8Prefix(data []byte) bool {
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		if r == utf8.RuneError && size == 1 {
+			return !utf8.FullRune(data)
+		}
+		data = data[size:]
+	}
+	return true
+}
+
+// SaveFileStrategy writes the file by the `on_exists` policy. The written file is recorded to the manifest
+// when it is created or when it is already recorded (the existing files of the user are not recorded).
 type SaveFileStrategy struct {
-	fileMode os.FileMode
+	modes    entity.FileModes
+	policy   *OnExistsPolicy
+	journal  *Journal
+	recorder *ManifestRecorder
 	logger   entity.Logger
 }
 
-func NewSaveFileStrategy(logger entity.Logger) *SaveFileStrategy {
+func NewSaveFileStrategy(
+	modes entity.FileModes,
+	policy *OnExistsPolicy,
+	journal *Journal,
+	recorder *ManifestRecorder,
+	logger entity.Logger) *SaveFileStrategy {
 	return &SaveFileStrategy{
-		fileMode: os.ModePerm,
+		modes:    modes,
+		policy:   policy,
+		journal:  journal,
+		recorder: recorder,
 		logger:   logger,
 	}
 }
 
 func (p *SaveFileStrategy) Apply(file entity.DataFile) (entity.DataFile, error) {
+	var (
+		filePath = file.Path()
+		data     = file.Data
+		created  = true
+	)
+
+	if info, err := os.Stat(filePath); err == nil {
+		created = false
+		policy, err := p.policy.Resolve(filePath, file.OnExists)
+		if err != nil {
+			return file, xerrors.Errorf("save file: %w", err)
+		}
+		switch policy {
+		case entity.OnExistsSkip:
+			p.logger.Infof("file exists, skip: %s", filePath)
+			return file, nil
+		case entity.OnExistsError:
+			return file, xerrors.Errorf("save file: file exists [%s]", filePath)
+		case entity.OnExistsBackup:
+			if err = p.backup(filePath, info.Mode().Perm()); err != nil {
+				return file, xerrors.Errorf("save file: %w", err)
+			}
+		case entity.OnExistsAppend, entity.OnExistsPrepend:
+			current, err := os.ReadFile(filePath)
+			if err != nil {
+				return file, xerrors.Errorf("save file: read existing file [%s]: %w", filePath, err)
+			}
+			if policy == entity.OnExistsAppend {
+				data = joinData(current, data)
+			} else {
+				data = joinData(data, current)
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		return file, xerrors.Errorf("save file: stat [%s]: %w", filePath, err)
+	}
+
+	if err := p.journal.Write(filePath); err != nil {
+		return file, xerrors.Errorf("save file: %w", err)
+	}
+
 	fileDir := file.Dir()
 	if _, err := os.Stat(fileDir); os.IsNotExist(err) {
-		err = os.MkdirAll(fileDir, p.fileMode)
+		if err = p.recorder.Dir(fileDir); err != nil {
+			return file, xerrors.Errorf("save file: %w", err)
+		}
+		err = mkdirAll(fileDir, p.modes.Dir)
 		if err != nil {
 			return file, xerrors.Errorf("save file: create file dir [%s]: %w", fileDir, err)
 		}
 	}
 
-	filePath := file.Path()
-	err := os.WriteFile(filePath, file.Data, p.fileMode)
+	var (
+		mode  = file.Mode
+		chmod = true
+	)
+	if mode == 0 {
+		mode = p.modes.File
+	}
+	if mode == 0 {
+		mode, chmod = os.ModePerm, false
+	}
+
+	err := os.WriteFile(filePath, data, mode)
 	if err != nil {
 		return file, xerrors.Errorf("save file: write file [%s]: %w", file.Name(), err)
 	}
+	// the mode of the created file is masked by umask, the mode of the existing file is not changed by writing
+	if chmod {
+		if err = os.Chmod(filePath, mode); err != nil {
+			return file, xerrors.Errorf("save file: change mode [%s]: %w", filePath, err)
+		}
+	}
+	if created || p.recorder.Tracked(filePath) {
+		if err = p.recorder.File(filePath, file.Source, data); err != nil {
+			return file, xerrors.Errorf("save file: %w", err)
+		}
+	}
 	p.logger.Infof("file saved: %s", filePath)
 	return file, nil
 }
 
+// backup copies the existing file to the `.orig` file.
+func (p *SaveFileStrategy) backup(path string, mode os.FileMode) error {
+	backupPath := path + backupFileExt
+	if err := p.journal.Write(backupPath); err != nil {
+		return err
+	}
+	if err := copyFile(path, backupPath, mode); err != nil {
+		return xerrors.Errorf("backup [%s]: %w", path, err)
+	}
+	p.logger.Infof("file backup saved: %s", backupPath)
+	return nil
+}
+
 type ReplacePathFileStrategy struct {
 	// paths contains old and new Files path to replace
 	paths map[string]string
@@ -110,10 +266,7 @@ func NewReplacePathFileStrategy(paths map[string]string) *ReplacePathFileStrateg
 func (p *ReplacePathFileStrategy) Apply(file entity.DataFile) (entity.DataFile, error) {
 	newPath, ok := p.paths[file.Path()]
 	if ok {
-		file = entity.DataFile{
-			FileInfo: entity.NewFileInfo(newPath),
-			Data:     file.Data,
-		}
+		file.FileInfo = entity.NewFileInfo(newPath)
 	}
 	return file, nil
 }
@@ -257,6 +410,10 @@ func (p *LocalProducer) Get() (entity.DataFile, error) {
 	return entity.DataFile{
 		FileInfo: p.file.FileInfo,
 		Data:     data,
+		Source:   entity.FileSourceLocal,
+		OnExists: p.file.OnExists,
+		Mode:     p.file.Mode,
+		Raw:      p.file.Raw,
 	}, nil
 }
 
@@ -291,6 +448,10 @@ func (p *RemoteProducer) Get() (entity.DataFile, error) {
 		return entity.DataFile{
 			FileInfo: p.file.FileInfo,
 			Data:     rs.Body(),
+			Source:   entity.FileSourceGet,
+			OnExists: p.file.OnExists,
+			Mode:     p.file.Mode,
+			Raw:      p.file.Raw,
 		}, nil
 
 	}
@@ -160,6 +160,40 @@ func Test_TemplateFileStrategy(t *testing.T) {
 		assert.Equal(t, file.Dir(), res.Dir())
 	})
 
+	t.Run("success_skip_raw_data", func(t *testing.T) {
+		str := NewTemplateFileStrategy(map[string]any{"v": "VAL"}, nil, nil, []string{"PNG", ".tar.gz"})
+		for _, file := range []entity.DataFile{
+			{FileInfo: entity.NewFileInfo("logo.png"), Data: []byte("{{ .v }}")},
+			{FileInfo: entity.NewFileInfo("dist.tar.gz"), Data: []byte("{{ .v }}")},
+			{FileInfo: entity.NewFileInfo("font.bin"), Data: []byte("{{ .v \x00")},
+			{FileInfo: entity.NewFileInfo("doc.pdf"), Data: []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3 {{ .v")},
+			{FileInfo: entity.NewFileInfo(name), Data: []byte("{{ .v }}"), Raw: true},
+		} {
+			res, err := str.Apply(file)
+			assert.NoError(t, err)
+			assert.True(t, res.Raw)
+			assert.Equal(t, file.Data, res.Data)
+		}
+
+		res, err := str.Apply(newDataFileFn("{{ .v }}"))
+		assert.NoError(t, err)
+		assert.False(t, res.Raw)
+		assert.Equal(t, "VAL", string(res.Data))
+	})
+	t.Run("success_process_text_with_binary_signature", func(t *testing.T) {
+		str := NewTemplateFileStrategy(map[string]any{"v": "VAL"}, nil, nil, nil)
+		for data, expected := range map[string]string{
+			"BM {{ .v }}":   "BM VAL",
+			"GIF8 {{ .v }}": "GIF8 VAL",
+			"%PDF-{{ .v }}": "%PDF-VAL",
+			"BM ✓ {{ .v }}": "BM ✓ VAL",
+		} {
+			res, err := str.Apply(newDataFileFn(data))
+			assert.NoError(t, err)
+			assert.False(t, res.Raw)
+			assert.Equal(t, expected, string(res.Data))
+		}
+	})
 	t.Run("success_exec_template_functions", func(t *testing.T) {
 		var (
 			templateValue = "VAL"
@@ -278,7 +312,7 @@ func Test_SaveFileStrategy(t *testing.T) {
 			}
 		)
 
-		res, err := NewSaveFileStrategy(mockLogger).Apply(in)
+		res, err := NewSaveFileStrategy(entity.FileModes{}, nil, nil, nil, mockLogger).Apply(in)
 		a.NoError(err)
 		a.Equal(in.Dir(), res.Dir())
 		a.Equal(in.Name(), res.Name())
@@ -292,3 +326,160 @@ func Test_SaveFileStrategy(t *testing.T) {
 		a.Equal(res.Data, resData)
 	})
 }
+
+func Test_SaveFileStrategy_onExists(t *testing.T) {
+	SkipSLowTest(t)
+
+	const (
+		someFile = "some_file.txt"
+	)
+
+	var (
+		oldData    = []byte("old")
+		newData    = []byte("new\n")
+		mockLogger = MockLogger{
+			infof: func(format string, args ...any) {},
+		}
+	)
+
+	apply := func(t *testing.T, tmpDir string, policy *OnExistsPolicy, onExists entity.OnExists) (string, error) {
+		t.Helper()
+		path := filepath.Join(tmpDir, someFile)
+		assert.NoError(t, os.WriteFile(path, oldData, os.ModePerm))
+		file := entity.DataFile{FileInfo: entity.NewFileInfo(path), Data: newData, OnExists: onExists}
+		_, err := NewSaveFileStrategy(entity.FileModes{}, policy, nil, nil, mockLogger).Apply(file)
+		return path, err
+	}
+
+	t.Run("success_overwrite_by_default", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			path, err := apply(t, tmpDir, nil, entity.Empty)
+			assert.NoError(t, err)
+			AssertFileDataEqual(t, path, newData)
+		})
+	})
+	t.Run("success_skip_by_default_policy", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			path, err := apply(t, tmpDir, NewOnExistsPolicy(entity.OnExistsSkip, nil), entity.Empty)
+			assert.NoError(t, err)
+			AssertFileDataEqual(t, path, oldData)
+		})
+	})
+	t.Run("success_file_policy_overrides_default_policy", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			path, err := apply(t, tmpDir, NewOnExistsPolicy(entity.OnExistsSkip, nil), entity.OnExistsOverwrite)
+			assert.NoError(t, err)
+			AssertFileDataEqual(t, path, newData)
+		})
+	})
+	t.Run("success_backup", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			path, err := apply(t, tmpDir, nil, entity.OnExistsBackup)
+			assert.NoError(t, err)
+			AssertFileDataEqual(t, path, newData)
+			AssertFileDataEqual(t, path+backupFileExt, oldData)
+		})
+	})
+	t.Run("success_append", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			path, err := apply(t, tmpDir, nil, entity.OnExistsAppend)
+			assert.NoError(t, err)
+			AssertFileDataEqual(t, path, []byte("old\nnew\n"))
+		})
+	})
+	t.Run("success_prepend", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			path, err := apply(t, tmpDir, nil, entity.OnExistsPrepend)
+			assert.NoError(t, err)
+			AssertFileDataEqual(t, path, []byte("new\nold"))
+		})
+	})
+	t.Run("success_prompt", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			policy := NewOnExistsPolicy(entity.OnExistsPrompt, func(path string) (entity.OnExists, error) {
+				assert.Equal(t, filepath.Join(tmpDir, someFile), path)
+				return entity.OnExistsBackup, nil
+			})
+			path, err := apply(t, tmpDir, policy, entity.Empty)
+			assert.NoError(t, err)
+			AssertFileDataEqual(t, path+backupFileExt, oldData)
+		})
+	})
+	t.Run("success_prompt_without_prompt_fn_skip", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			path, err := apply(t, tmpDir, nil, entity.OnExistsPrompt)
+			assert.NoError(t, err)
+			AssertFileDataEqual(t, path, oldData)
+		})
+	})
+	t.Run("success_not_existing_file_saved", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			path := filepath.Join(tmpDir, someFile)
+			file := entity.DataFile{FileInfo: entity.NewFileInfo(path), Data: newData, OnExists: entity.OnExistsError}
+			_, err := NewSaveFileStrategy(entity.FileModes{}, nil, nil, nil, mockLogger).Apply(file)
+			assert.NoError(t, err)
+			AssertFileDataEqual(t, path, newData)
+		})
+	})
+	t.Run("error_file_exists", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			path, err := apply(t, tmpDir, nil, entity.OnExistsError)
+			assert.Error(t, err)
+			AssertFileDataEqual(t, path, oldData)
+		})
+	})
+	t.Run("error_unknown_policy", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			_, err := apply(t, tmpDir, nil, entity.OnExists("unknown"))
+			assert.Error(t, err)
+		})
+	})
+}
+
+func Test_SaveFileStrategy_mode(t *testing.T) {
+	SkipSLowTest(t)
+
+	const (
+		someDir  = "some_dir"
+		someFile = "some_file.sh"
+	)
+
+	var (
+		someData   = []byte("some data")
+		mockLogger = MockLogger{
+			infof: func(format string, args ...any) {},
+		}
+	)
+
+	assertMode := func(t *testing.T, path string, exp os.FileMode) {
+		t.Helper()
+		info, err := os.Stat(path)
+		assert.NoError(t, err)
+		assert.Equal(t, exp, info.Mode().Perm())
+	}
+
+	t.Run("success_default_modes", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				path  = filepath.Join(tmpDir, someDir, someFile)
+				modes = entity.FileModes{File: 0o600, Dir: 0o700}
+			)
+			_, err := NewSaveFileStrategy(modes, nil, nil, nil, mockLogger).
+				Apply(entity.DataFile{FileInfo: entity.NewFileInfo(path), Data: someData})
+			assert.NoError(t, err)
+			assertMode(t, path, 0o600)
+			assertMode(t, filepath.Dir(path), 0o700)
+		})
+	})
+	t.Run("success_file_mode_overrides_default_mode", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			path := filepath.Join(tmpDir, someFile)
+			assert.NoError(t, os.WriteFile(path, someData, 0o644))
+
+			_, err := NewSaveFileStrategy(entity.FileModes{File: 0o600}, nil, nil, nil, mockLogger).
+				Apply(entity.DataFile{FileInfo: entity.NewFileInfo(path), Data: someData, Mode: 0o755})
+			assert.NoError(t, err)
+			assertMode(t, path, 0o755)
+		})
+	})
+}
@@ -0,0 +1,204 @@
+package exec
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"golang.org/x/xerrors"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+// IgnoreFile is the file of the processed dir, which declares the ignored paths (`.gitignore` syntax).
+const IgnoreFile = ".progenignore"
+
+// defaultIgnore contains the ignored paths, which can be included again by the negated patterns of [IgnoreFile].
+var defaultIgnore = []string{".git/", "/" + IgnoreFile}
+
+// PathFilter selects the paths (relative to the root, separated by `/`) by the [doublestar] patterns
+// and by the ignore rules of the root.
+type PathFilter struct {
+	include []string
+	exclude []string
+	ignore  []ignoreRule
+}
+
+func NewPathFilter(include, exclude []string) (*PathFilter, error) {
+	for _, pattern := range slices.Concat(include, exclude) {
+		if !doublestar.ValidatePattern(pattern) {
+			return nil, xerrors.Errorf("path filter: invalid pattern [%s]", pattern)
+		}
+	}
+	return &PathFilter{
+		include: include,
+		exclude: exclude,
+	}, nil
+}
+
+// NewDirPathFilter returns the filter of the dir, which also excludes the paths ignored by default
+// (`.git`, [IgnoreFile]) and by the [IgnoreFile] of the dir.
+func NewDirPathFilter(dir string, include, exclude []string) (*PathFilter, error) {
+	filter, err := NewPathFilter(include, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := defaultIgnore
+	data, err := os.ReadFile(filepath.Join(dir, IgnoreFile))
+	switch {
+	case err == nil:
+		lines = slices.Concat(lines, strings.Split(string(data), entity.NewLine))
+	case !os.IsNotExist(err):
+		return nil, xerrors.Errorf("path filter: read ignore file: %w", err)
+	}
+
+	filter.ignore, err = parseIgnore(lines)
+	if err != nil {
+		return nil, xerrors.Errorf("path filter: %s: %w", filepath.Join(dir, IgnoreFile), err)
+	}
+	return filter, nil
+}
+
+// Excluded reports whether the path matches any `exclude` pattern or is ignored
+// (the subtree of the excluded dir is excluded too).
+func (f *PathFilter) Excluded(path string, isDir bool) bool {
+	if f == nil {
+		return false
+	}
+	return matchAny(f.exclude, path) || ignored(f.ignore, path, isDir)
+}
+
+// Included reports whether the file's path matches any `include` pattern, all paths are included without patterns.
+func (f *PathFilter) Included(path string) bool {
+	if f == nil || len(f.include) == 0 {
+		return true
+	}
+	return matchAny(f.include, path)
+}
+
+func matchAny(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if doublestar.MatchUnvalidated(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoreRule is the pattern of the ignore file.
+type ignoreRule struct {
+	pattern string
+	negate  bool
+	dirOnly bool
+}
+
+// parseIgnore parses the lines of `.gitignore` syntax: the patterns without `/` (except the trailing one)
+// match at any level, the pattern with the trailing `/` matches only dirs, `!` negates the pattern.
+func parseIgnore(lines []string) ([]ignoreRule, error) {
+	rules := make([]ignoreRule, 0, len(lines))
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == entity.Empty || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule ignoreRule
+		if rule.negate = strings.HasPrefix(line, "!"); rule.negate {
+			line = line[1:]
+		}
+		// the escaped leading `#` and `!`
+		if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+			line = line[1:]
+		}
+		if rule.dirOnly = strings.HasSuffix(line, "/"); rule.dirOnly {
+			line = strings.TrimSuffix(line, "/")
+		}
+
+		if strings.Contains(line, "/") {
+			line = strings.TrimPrefix(line, "/")
+		} else {
+			line = "**/" + line
+		}
+		if line == entity.Empty || !doublestar.ValidatePattern(line) {
+			return nil, xerrors.Errorf("invalid pattern [%s] (line %d)", lines[i], i+1)
+		}
+		rule.pattern = line
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// ignored reports whether the path is ignored by the rules, the last matched rule wins.
+func ignored(rules []ignoreRule, path string, isDir bool) bool {
+	var res bool
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if doublestar.MatchUnvalidated(rule.pattern, path) {
+			res = !rule.negate
+		}
+	}
+	return res
+}
+
+// LocalFS is the file system of the local dir, which contains the paths selected by the filter.
+// The symbolic links of the dir are not followed (see [LocalFS.ReadLink]).
+type LocalFS struct {
+	fs.FS
+	dir    string
+	filter *PathFilter
+}
+
+func NewLocalFS(dir string, filter *PathFilter) *LocalFS {
+	return &LocalFS{
+		FS:     os.DirFS(dir),
+		dir:    dir,
+		filter: filter,
+	}
+}
+
+// ReadDir returns the entries of the dir, which are not excluded. The dir entry is returned
+// when the dir contains the included files.
+func (l *LocalFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(l.FS, name)
+	if err != nil {
+		return nil, err
+	}
+	res := entries[:0]
+	for _, entry := range entries {
+		entPath := path.Join(name, entry.Name())
+		switch {
+		case l.filter.Excluded(entPath, entry.IsDir()):
+		case entry.IsDir():
+			if l.containsIncluded(entPath) {
+				res = append(res, entry)
+			}
+		case l.filter.Included(entPath):
+			res = append(res, entry)
+		}
+	}
+	return res, nil
+}
+
+func (l *LocalFS) containsIncluded(dir string) bool {
+	if l.filter == nil || len(l.filter.include) == 0 {
+		return true
+	}
+	entries, err := l.ReadDir(dir)
+	// the error is returned by the walk of the dir
+	return err != nil || len(entries) > 0
+}
+
+// ReadLink returns the target of the symbolic link.
+func (l *LocalFS) ReadLink(name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return entity.Empty, &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	return os.Readlink(filepath.Join(l.dir, filepath.FromSlash(name)))
+}
@@ -0,0 +1,130 @@
+package exec
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PathFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success_exclude", func(t *testing.T) {
+		filter, err := NewPathFilter(nil, []string{"node_modules", "**/*.png"})
+		assert.NoError(t, err)
+		assert.True(t, filter.Excluded("node_modules", true))
+		assert.True(t, filter.Excluded("assets/logo.png", false))
+		assert.False(t, filter.Excluded("src/node_modules", true))
+		assert.True(t, filter.Included("src/main.go"))
+	})
+	t.Run("success_include", func(t *testing.T) {
+		filter, err := NewPathFilter([]string{"**/*.go"}, nil)
+		assert.NoError(t, err)
+		assert.True(t, filter.Included("main.go"))
+		assert.True(t, filter.Included("cmd/app/main.go"))
+		assert.False(t, filter.Included("readme.md"))
+	})
+	t.Run("success_nil_filter", func(t *testing.T) {
+		var filter *PathFilter
+		assert.False(t, filter.Excluded("some", false))
+		assert.True(t, filter.Included("some"))
+	})
+	t.Run("error_invalid_pattern", func(t *testing.T) {
+		_, err := NewPathFilter([]string{"[a"}, nil)
+		assert.Error(t, err)
+	})
+}
+
+func Test_NewDirPathFilter(t *testing.T) {
+	SkipSLowTest(t)
+
+	t.Run("success_default_ignore", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			filter, err := NewDirPathFilter(tmpDir, nil, nil)
+			assert.NoError(t, err)
+			assert.True(t, filter.Excluded(".git", true))
+			assert.True(t, filter.Excluded("sub/.git", true))
+			assert.True(t, filter.Excluded(IgnoreFile, false))
+			assert.False(t, filter.Excluded("sub/"+IgnoreFile, false))
+			assert.False(t, filter.Excluded(".gitignore", false))
+		})
+	})
+	t.Run("success_ignore_file", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			CreateFile(t, filepath.Join(tmpDir, IgnoreFile), []byte(strings.Join([]string{
+				"# comment",
+				"*.png",
+				"!keep.png",
+				"build/",
+				"/docs/*.md",
+				"!.git/",
+			}, "\n")))
+
+			filter, err := NewDirPathFilter(tmpDir, nil, []string{"tmp"})
+			assert.NoError(t, err)
+			assert.True(t, filter.Excluded("logo.png", false))
+			assert.True(t, filter.Excluded("assets/logo.png", false))
+			assert.False(t, filter.Excluded("assets/keep.png", false))
+			assert.True(t, filter.Excluded("src/build", true))
+			assert.False(t, filter.Excluded("src/build", false))
+			assert.True(t, filter.Excluded("docs/index.md", false))
+			assert.False(t, filter.Excluded("src/docs/index.md", false))
+			assert.True(t, filter.Excluded("tmp", true))
+			// the default ignored path is included again
+			assert.False(t, filter.Excluded(".git", true))
+		})
+	})
+	t.Run("error_invalid_ignore_pattern", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			CreateFile(t, filepath.Join(tmpDir, IgnoreFile), []byte("[a"))
+
+			_, err := NewDirPathFilter(tmpDir, nil, nil)
+			assert.Error(t, err)
+		})
+	})
+}
+
+func Test_LocalFS(t *testing.T) {
+	SkipSLowTest(t)
+
+	walk := func(t *testing.T, fsys fs.FS) []string {
+		t.Helper()
+		var paths []string
+		err := fs.WalkDir(fsys, ".", func(path string, _ fs.DirEntry, err error) error {
+			paths = append(paths, path)
+			return err
+		})
+		assert.NoError(t, err)
+		return paths
+	}
+
+	t.Run("success_filter", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			CreateFile(t, filepath.Join(tmpDir, "main.go"), nil)
+			CreateFile(t, filepath.Join(tmpDir, "readme.md"), nil)
+			CreateFile(t, filepath.Join(tmpDir, "cmd", "app.go"), nil)
+			CreateFile(t, filepath.Join(tmpDir, "docs", "index.md"), nil)
+			CreateFile(t, filepath.Join(tmpDir, "vendor", "lib.go"), nil)
+
+			filter, err := NewPathFilter([]string{"**/*.go"}, []string{"vendor"})
+			assert.NoError(t, err)
+
+			// the dirs without the included files are skipped
+			assert.Equal(t, []string{".", "cmd", "cmd/app.go", "main.go"}, walk(t, NewLocalFS(tmpDir, filter)))
+		})
+	})
+	t.Run("success_read_link", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			CreateFile(t, filepath.Join(tmpDir, "main.go"), nil)
+			assert.NoError(t, os.Symlink("main.go", filepath.Join(tmpDir, "link.go")))
+
+			target, err := NewLocalFS(tmpDir, nil).ReadLink("link.go")
+			assert.NoError(t, err)
+			assert.Equal(t, "main.go", target)
+		})
+	})
+}
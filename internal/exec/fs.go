@@ -12,37 +12,60 @@ import (
 
 type FileSystemModifyStrategy struct {
 	logger         entity.Logger
+	filterFn       func(dir string) (*PathFilter, error)
 	strategiesFn   func(paths map[string]string) []entity.FileStrategy
 	templateProcFn func() entity.TemplateProc
 	dirExecutorFn  func(dirs []string) entity.Executor
 	fileExecutorFn func(producers []entity.FileProducer, strategies []entity.FileStrategy) entity.Executor
+	linkExecutorFn func(links []entity.Link) entity.Executor
 	removeAllFn    func(path string) error
+	renameFn       func(oldPath, newPath string) error
 }
 
 func NewFileSystemModifyStrategy(
+	include,
+	exclude []string,
 	templateData,
 	templateFns map[string]any,
-	templateOptions []string,
+	templateOptions,
+	skipExt []string,
+	modes entity.FileModes,
+	journal *Journal,
+	recorder *ManifestRecorder,
 	logger entity.Logger) *FileSystemModifyStrategy {
 	return &FileSystemModifyStrategy{
 		logger: logger,
+		filterFn: func(dir string) (*PathFilter, error) {
+			return NewDirPathFilter(dir, include, exclude)
+		},
 		strategiesFn: func(paths map[string]string) []entity.FileStrategy {
 			return []entity.FileStrategy{
-				NewTemplateFileStrategy(templateData, templateFns, templateOptions),
+				NewTemplateFileStrategy(templateData, templateFns, templateOptions, skipExt),
 				NewReplacePathFileStrategy(paths),
-				NewSaveFileStrategy(logger),
+				NewManifestFileStrategy(recorder, entity.FileSourceFs),
+				// the files are modified in place, so the `on_exists` policy is not applied
+				NewSaveFileStrategy(modes, nil, journal, recorder, logger),
 			}
 		},
 		templateProcFn: func() entity.TemplateProc {
 			return entity.NewTemplateProc(templateData, templateFns, templateOptions)
 		},
 		dirExecutorFn: func(dirs []string) entity.Executor {
-			return NewDirExecutor(dirs, []entity.DirStrategy{NewMkdirAllStrategy(logger)})
+			return NewDirExecutor(dirs, []entity.DirStrategy{
+				NewManifestDirStrategy(recorder),
+				NewMkdirAllStrategy(modes.Dir, nil, journal, logger),
+			})
 		},
 		fileExecutorFn: func(producers []entity.FileProducer, strategies []entity.FileStrategy) entity.Executor {
 			return NewFilesExecutor(producers, strategies)
 		},
-		removeAllFn: os.RemoveAll,
+		linkExecutorFn: func(links []entity.Link) entity.Executor {
+			return NewLinksExecutor(links, []entity.LinkStrategy{
+				NewLinkStrategy(modes.Dir, nil, journal, recorder, logger),
+			})
+		},
+		removeAllFn: journalRemoveAllFn(journal),
+		renameFn:    journalRenameFn(journal),
 	}
 }
 
@@ -51,29 +74,64 @@ func (e *FileSystemModifyStrategy) Apply(dir string) (string, error) {
 		Entity struct {
 			Path  string
 			IsDir bool
+			Mode  fs.FileMode
+			// Target is the target of the symbolic link
+			Target string
 		}
 	)
 	var (
 		entitySet = make(map[string]Entity)
 		filePaths = make(map[string]string)
+		// skipped paths, which are moved when the parent's path is changed
+		moves = make(map[string]string)
 	)
 
-	err := filepath.Walk(dir, func(path string, info fs.FileInfo, err error) error {
+	filter, err := e.filterFn(dir)
+	if err != nil {
+		return entity.Empty, xerrors.Errorf("fs modify: %w", err)
+	}
+
+	err = filepath.Walk(dir, func(path string, info fs.FileInfo, err error) error {
 		if info == nil {
 			return err
 		}
 		if dir == path {
 			return err
 		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return xerrors.Errorf("fs modify: relative path [%s]: %w", path, err)
+		}
+		rel = filepath.ToSlash(rel)
+		if filter.Excluded(rel, info.IsDir()) || !info.IsDir() && !filter.Included(rel) {
+			parentDir := filepath.Dir(path)
+			if parent, ok := entitySet[parentDir]; ok && parent.Path != parentDir {
+				moves[path] = filepath.Join(parent.Path, info.Name())
+			}
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
 		entPath, err := e.templateProcFn().Process(path, path)
 		if err != nil {
 			return xerrors.Errorf("fs modify: process template to path [%s]: %w", path, err)
 		}
-		filePaths[path] = entPath
-		entitySet[path] = Entity{
+		ent := Entity{
 			Path:  entPath,
 			IsDir: info.IsDir(),
+			Mode:  info.Mode().Perm(),
 		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			ent.Target, err = os.Readlink(path)
+			if err != nil {
+				return xerrors.Errorf("fs modify: read link [%s]: %w", path, err)
+			}
+		}
+		filePaths[path] = entPath
+		entitySet[path] = ent
 		return err
 	})
 	if err != nil {
@@ -83,17 +141,24 @@ func (e *FileSystemModifyStrategy) Apply(dir string) (string, error) {
 	var (
 		dirs          = make([]string, 0, len(entitySet))
 		fileProducers = make([]entity.FileProducer, 0, len(entitySet))
+		links         []entity.Link
 	)
 
 	for old, ent := range entitySet {
-		if ent.IsDir {
+		switch {
+		case ent.IsDir:
 			dirs = append(dirs, ent.Path)
-		}
-
-		if !ent.IsDir {
+		case ent.Target != entity.Empty:
+			// the symbolic link is not followed, it is only moved when the path is changed
+			if ent.Path != old {
+				links = append(links, entity.Link{Path: ent.Path, Target: ent.Target})
+			}
+		default:
 			file := entity.LocalFile{
 				FileInfo:  entity.NewFileInfo(ent.Path),
 				LocalPath: old,
+				// the source file's mode is preserved
+				Mode: ent.Mode,
 			}
 			fileProducers = append(fileProducers, NewLocalProducer(file))
 		}
@@ -111,6 +176,20 @@ func (e *FileSystemModifyStrategy) Apply(dir string) (string, error) {
 		return entity.Empty, xerrors.Errorf("fs modify: files execute: %w", err)
 	}
 
+	linkExec := e.linkExecutorFn(links)
+	err = linkExec.Exec()
+	if err != nil {
+		return entity.Empty, xerrors.Errorf("fs modify: links execute: %w", err)
+	}
+
+	for old, path := range moves {
+		err = e.renameFn(old, path)
+		if err != nil {
+			return entity.Empty, xerrors.Errorf("fs modify: move skipped [%s]: %w", old, err)
+		}
+		e.logger.Infof("fs modify: move: %s -> %s", old, path)
+	}
+
 	for old, ent := range entitySet {
 		if ent.Path == old {
 			continue
@@ -2,6 +2,7 @@ package exec
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -57,6 +58,9 @@ func Test_FileSystemModifyStrategy(t *testing.T) {
 			CreateFile(t, pathTempC, dataC)
 
 			str := FileSystemModifyStrategy{
+				filterFn: func(dir string) (*PathFilter, error) {
+					return nil, nil
+				},
 				templateProcFn: func() entity.TemplateProc {
 					return entity.NewTemplateProc(templateData, nil, nil)
 				},
@@ -72,6 +76,10 @@ func Test_FileSystemModifyStrategy(t *testing.T) {
 					a.NotEmpty(strategies)
 					return MockExecutor{}
 				},
+				linkExecutorFn: func(links []entity.Link) entity.Executor {
+					a.Empty(links)
+					return MockExecutor{}
+				},
 				removeAllFn: func(old string) error {
 					a.NotEmpty(old)
 					return nil
@@ -106,7 +114,7 @@ func Test_FileSystemModifyStrategy(t *testing.T) {
 			CreateFile(t, pathTempB, dataB)
 			CreateFile(t, pathTempC, dataC)
 
-			str := NewFileSystemModifyStrategy(templateData, nil, nil, mockLogger)
+			str := NewFileSystemModifyStrategy(nil, nil, templateData, nil, nil, nil, entity.FileModes{}, nil, nil, mockLogger)
 
 			dir, err := str.Apply(tmpDir)
 			a.NoError(err)
@@ -120,6 +128,64 @@ func Test_FileSystemModifyStrategy(t *testing.T) {
 			AssertFileDataEqual(t, expectedPathC, dataC)
 		})
 	})
+	t.Run("success_skip_excluded_and_ignored", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a          = assert.New(t)
+				mockLogger = MockLogger{
+					infof: func(format string, args ...any) {},
+				}
+				gitFile      = filepath.Join(tmpDir, ".git", "HEAD")
+				ignoredFile  = filepath.Join(tmpDir, "{{ .var }}", "logo.png")
+				excludedFile = filepath.Join(tmpDir, "{{ .var }}", "vendor", "lib.txt")
+			)
+
+			CreateFile(t, filepath.Join(tmpDir, IgnoreFile), []byte("*.png"))
+			CreateFile(t, gitFile, dataA)
+			CreateFile(t, filepath.Join(tmpDir, pathB), dataA)
+			CreateFile(t, ignoredFile, dataA)
+			CreateFile(t, excludedFile, dataA)
+
+			_, err := NewFileSystemModifyStrategy(nil, []string{"**/vendor"}, templateData, nil, nil, nil, entity.FileModes{}, nil, nil, mockLogger).Apply(tmpDir)
+			a.NoError(err)
+
+			AssertFileDataEqual(t, gitFile, dataA)
+			AssertFileDataEqual(t, filepath.Join(tmpDir, expectedSubPathB), []byte(varTemplateVariableValue))
+			// the skipped paths are not processed, but moved to the new parent's path
+			AssertFileDataEqual(t, filepath.Join(tmpDir, varTemplateVariableValue, "logo.png"), dataA)
+			AssertFileDataEqual(t, filepath.Join(tmpDir, varTemplateVariableValue, "vendor", "lib.txt"), dataA)
+			a.NoDirExists(filepath.Join(tmpDir, "{{ .var }}"))
+		})
+	})
+	t.Run("success_preserve_symlink", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a          = assert.New(t)
+				mockLogger = MockLogger{
+					infof: func(format string, args ...any) {},
+				}
+				pathTempA     = filepath.Join(tmpDir, pathA)
+				linkPath      = filepath.Join(tmpDir, "link.txt")
+				linkTmplPath  = filepath.Join(tmpDir, "{{ .var }}.lnk")
+				expectedLinkB = filepath.Join(tmpDir, varTemplateVariableValue+".lnk")
+			)
+
+			CreateFile(t, pathTempA, dataA)
+			a.NoError(os.Symlink(pathA, linkPath))
+			a.NoError(os.Symlink(pathA, linkTmplPath))
+
+			_, err := NewFileSystemModifyStrategy(nil, nil, templateData, nil, nil, nil, entity.FileModes{}, nil, nil, mockLogger).Apply(tmpDir)
+			a.NoError(err)
+
+			AssertFileDataEqual(t, pathTempA, []byte(varTemplateVariableValue))
+			for _, path := range []string{linkPath, expectedLinkB} {
+				target, err := os.Readlink(path)
+				a.NoError(err)
+				a.Equal(pathA, target)
+			}
+			a.NoFileExists(linkTmplPath)
+		})
+	})
 }
 
 func Test_DryRunFileSystemModifyStrategy(t *testing.T) {
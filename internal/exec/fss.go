@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
-	"os"
 	"path/filepath"
 
 	"golang.org/x/xerrors"
@@ -12,41 +11,62 @@ import (
 	"github.com/kozmod/progen/internal/entity"
 )
 
+// ownerWritePerm is the write permission of the owner.
+const ownerWritePerm fs.FileMode = 0o200
+
 type FileSystemSaveStrategy struct {
 	fs             fs.FS
+	raw            bool
 	logger         entity.Logger
 	strategiesFn   func() []entity.FileStrategy
 	templateProcFn func() entity.TemplateProc
 	dirExecutorFn  func(dirs []string) entity.Executor
 	fileExecutorFn func(producers []entity.FileProducer, strategies []entity.FileStrategy) entity.Executor
+	linkExecutorFn func(links []entity.Link) entity.Executor
 	removeAllFn    func(path string) error
 }
 
 func NewFileSystemSaveStrategy(
 	fs fs.FS,
+	raw bool,
 	templateData,
 	templateFns map[string]any,
-	templateOptions []string,
+	templateOptions,
+	skipExt []string,
+	modes entity.FileModes,
+	policy *OnExistsPolicy,
+	journal *Journal,
+	recorder *ManifestRecorder,
 	logger entity.Logger) *FileSystemSaveStrategy {
 	return &FileSystemSaveStrategy{
 		fs:     fs,
+		raw:    raw,
 		logger: logger,
 		strategiesFn: func() []entity.FileStrategy {
 			return []entity.FileStrategy{
-				NewTemplateFileStrategy(templateData, templateFns, templateOptions),
-				NewSaveFileStrategy(logger),
+				NewTemplateFileStrategy(templateData, templateFns, templateOptions, skipExt),
+				NewManifestFileStrategy(recorder, entity.FileSourceFs),
+				NewSaveFileStrategy(modes, policy, journal, recorder, logger),
 			}
 		},
 		templateProcFn: func() entity.TemplateProc {
 			return entity.NewTemplateProc(templateData, templateFns, templateOptions)
 		},
 		dirExecutorFn: func(dirs []string) entity.Executor {
-			return NewDirExecutor(dirs, []entity.DirStrategy{NewMkdirAllStrategy(logger)})
+			return NewDirExecutor(dirs, []entity.DirStrategy{
+				NewManifestDirStrategy(recorder),
+				NewMkdirAllStrategy(modes.Dir, nil, journal, logger),
+			})
 		},
 		fileExecutorFn: func(producers []entity.FileProducer, strategies []entity.FileStrategy) entity.Executor {
 			return NewFilesExecutor(producers, strategies)
 		},
-		removeAllFn: os.RemoveAll,
+		linkExecutorFn: func(links []entity.Link) entity.Executor {
+			return NewLinksExecutor(links, []entity.LinkStrategy{
+				NewLinkStrategy(modes.Dir, policy, journal, recorder, logger),
+			})
+		},
+		removeAllFn: journalRemoveAllFn(journal),
 	}
 }
 
@@ -54,9 +74,12 @@ func (e *FileSystemSaveStrategy) Apply(targetDir string) (string, error) {
 	var (
 		dirs          []string
 		fileProducers []entity.FileProducer
+		links         []entity.Link
 		root          = entity.Dot
 	)
 
+	linkFS, isLinkFS := e.fs.(readLinkFS)
+
 	err := fs.WalkDir(e.fs, root, func(path string, info fs.DirEntry, err error) error {
 		if info == nil {
 			return err
@@ -76,6 +99,13 @@ func (e *FileSystemSaveStrategy) Apply(targetDir string) (string, error) {
 		switch {
 		case info.IsDir():
 			dirs = append(dirs, entPath)
+		case isLinkFS && info.Type()&fs.ModeSymlink != 0:
+			// the symbolic link is not followed when the file system is able to read the link's target
+			target, err := linkFS.ReadLink(path)
+			if err != nil {
+				return fmt.Errorf("fs save: read fs link [%s]: %v", path, err)
+			}
+			links = append(links, entity.Link{Path: entPath, Target: target})
 		default:
 			srcFile, err = e.fs.Open(path)
 			if err != nil {
@@ -90,11 +120,25 @@ func (e *FileSystemSaveStrategy) Apply(targetDir string) (string, error) {
 				return fmt.Errorf("fs save: read fs file [%s]: %v", path, err)
 			}
 
+			fileInfo, err := info.Info()
+			if err != nil {
+				return fmt.Errorf("fs save: stat fs file [%s]: %v", path, err)
+			}
+			// the source file's mode is preserved, the file stays writable by the owner
+			// (the files of `embed.FS` are read-only), the zero mode is replaced by the default mode
+			mode := fileInfo.Mode().Perm()
+			if mode != 0 {
+				mode |= ownerWritePerm
+			}
+
 			fileProducers = append(fileProducers,
 				NewDummyProducer(
 					entity.DataFile{
 						FileInfo: entity.NewFileInfo(entPath),
 						Data:     data,
+						Source:   entity.FileSourceFs,
+						Mode:     mode,
+						Raw:      e.raw,
 					},
 				),
 			)
@@ -117,6 +161,12 @@ func (e *FileSystemSaveStrategy) Apply(targetDir string) (string, error) {
 		return entity.Empty, xerrors.Errorf("fs save: files execute: %w", err)
 	}
 
+	linkExec := e.linkExecutorFn(links)
+	err = linkExec.Exec()
+	if err != nil {
+		return entity.Empty, xerrors.Errorf("fs save: links execute: %w", err)
+	}
+
 	return targetDir, nil
 }
 
@@ -8,6 +8,8 @@ import (
 	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/kozmod/progen/internal/entity"
 )
 
 func Test_FileSystemSaveStrategy(t *testing.T) {
@@ -62,7 +64,7 @@ func Test_FileSystemSaveStrategy(t *testing.T) {
 				},
 			}
 
-			str := NewFileSystemSaveStrategy(fs, templateData, nil, nil, mockLogger)
+			str := NewFileSystemSaveStrategy(fs, false, templateData, nil, nil, nil, entity.FileModes{}, nil, nil, nil, mockLogger)
 
 			dir, err := str.Apply(tmpDirTarget)
 			a.NoError(err)
@@ -102,7 +104,7 @@ func Test_FileSystemSaveStrategy(t *testing.T) {
 
 			fs := os.DirFS(tmpDir)
 
-			str := NewFileSystemSaveStrategy(fs, templateData, nil, nil, mockLogger)
+			str := NewFileSystemSaveStrategy(fs, false, templateData, nil, nil, nil, entity.FileModes{}, nil, nil, nil, mockLogger)
 
 			dir, err := str.Apply(tmpDirTarget)
 			a.NoError(err)
@@ -116,6 +118,79 @@ func Test_FileSystemSaveStrategy(t *testing.T) {
 			AssertFileDataEqual(t, expectedPathC, dataC)
 		})
 	})
+	t.Run("success_preserve_mode", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a          = assert.New(t)
+				mockLogger = MockLogger{
+					infof: func(format string, args ...any) {},
+				}
+				fs = fstest.MapFS{
+					"run.sh":    {Data: dataB, Mode: 0o755},
+					"readme.md": {Data: dataC, Mode: 0o444},
+				}
+			)
+
+			_, err := NewFileSystemSaveStrategy(fs, false, templateData, nil, nil, nil, entity.FileModes{}, nil, nil, nil, mockLogger).Apply(tmpDir)
+			a.NoError(err)
+
+			info, err := os.Stat(filepath.Join(tmpDir, "run.sh"))
+			a.NoError(err)
+			a.Equal(os.FileMode(0o755), info.Mode().Perm())
+
+			// the read-only file stays writable by the owner
+			info, err = os.Stat(filepath.Join(tmpDir, "readme.md"))
+			a.NoError(err)
+			a.Equal(os.FileMode(0o644), info.Mode().Perm())
+		})
+	})
+	t.Run("success_raw", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a          = assert.New(t)
+				mockLogger = MockLogger{
+					infof: func(format string, args ...any) {},
+				}
+				fs = fstest.MapFS{
+					pathB: {Data: dataA},
+				}
+			)
+
+			_, err := NewFileSystemSaveStrategy(fs, true, templateData, nil, nil, nil, entity.FileModes{}, nil, nil, nil, mockLogger).Apply(tmpDir)
+			a.NoError(err)
+
+			// the path is processed, the data is saved as is
+			AssertFileDataEqual(t, filepath.Join(tmpDir, expectedSubPathB), dataA)
+		})
+	})
+	t.Run("success_preserve_symlink", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a          = assert.New(t)
+				mockLogger = MockLogger{
+					infof: func(format string, args ...any) {},
+				}
+				srcDir    = filepath.Join(tmpDir, "src")
+				targetDir = filepath.Join(tmpDir, "result")
+			)
+
+			srcFS, ok := os.DirFS(srcDir).(readLinkFS)
+			if !ok {
+				t.Skip("os.DirFS does not read links")
+			}
+
+			CreateFile(t, filepath.Join(srcDir, pathA), dataA)
+			a.NoError(os.Symlink(pathA, filepath.Join(srcDir, "{{ .var }}.lnk")))
+
+			_, err := NewFileSystemSaveStrategy(srcFS, false, templateData, nil, nil, nil, entity.FileModes{}, nil, nil, nil, mockLogger).Apply(targetDir)
+			a.NoError(err)
+
+			target, err := os.Readlink(filepath.Join(targetDir, varTemplateVariableValue+".lnk"))
+			a.NoError(err)
+			a.Equal(pathA, target)
+			AssertFileDataEqual(t, filepath.Join(targetDir, pathA), []byte(varTemplateVariableValue))
+		})
+	})
 }
 
 func Test_DryRunFileSystemSaveStrategy(t *testing.T) {
@@ -0,0 +1,303 @@
+package exec
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+const (
+	journalBackupDirPattern = "progen-journal-"
+)
+
+type journalOp int
+
+const (
+	journalOpCreate journalOp = iota
+	journalOpOverwrite
+	journalOpRemove
+)
+
+type journalRecord struct {
+	op     journalOp
+	path   string
+	backup string
+}
+
+// Journal records the changes of the file system (created, overwritten and removed paths) and backs up
+// the overwritten and removed content to restore the original tree by [Journal.Rollback].
+// Methods of the nil Journal do nothing.
+type Journal struct {
+	mx        sync.Mutex
+	records   []journalRecord
+	backupDir string
+	logger    entity.Logger
+}
+
+func NewJournal(logger entity.Logger) *Journal {
+	return &Journal{
+		logger: logger,
+	}
+}
+
+// Mkdir records the directories, which are going to be created by [os.MkdirAll].
+func (j *Journal) Mkdir(dir string) error {
+	if j == nil {
+		return nil
+	}
+
+	created, err := notExistingDirs(dir)
+	if err != nil {
+		return xerrors.Errorf("journal: %w", err)
+	}
+	if len(created) == 0 {
+		return nil
+	}
+
+	// the first not existing parent is the root of the created directories
+	j.mx.Lock()
+	defer j.mx.Unlock()
+	j.records = append(j.records, journalRecord{op: journalOpCreate, path: created[len(created)-1]})
+	return nil
+}
+
+// Write records the file, which is going to be written, the existing file is backed up.
+func (j *Journal) Write(path string) error {
+	if j == nil {
+		return nil
+	}
+	if err := j.Mkdir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return j.backup(path, journalOpOverwrite)
+}
+
+// Remove records the path, which is going to be removed, the existing path is backed up.
+func (j *Journal) Remove(path string) error {
+	if j == nil {
+		return nil
+	}
+	return j.backup(path, journalOpRemove)
+}
+
+func (j *Journal) backup(path string, op journalOp) error {
+	j.mx.Lock()
+	defer j.mx.Unlock()
+
+	if _, err := os.Lstat(path); os.IsNotExist(err) {
+		if op == journalOpOverwrite {
+			j.records = append(j.records, journalRecord{op: journalOpCreate, path: path})
+		}
+		return nil
+	} else if err != nil {
+		return xerrors.Errorf("journal: stat [%s]: %w", path, err)
+	}
+
+	if j.backupDir == entity.Empty {
+		dir, err := os.MkdirTemp(entity.Empty, journalBackupDirPattern)
+		if err != nil {
+			return xerrors.Errorf("journal: create backup dir: %w", err)
+		}
+		j.backupDir = dir
+	}
+
+	backup := filepath.Join(j.backupDir, strconv.Itoa(len(j.records)))
+	if err := copyPath(path, backup); err != nil {
+		return xerrors.Errorf("journal: backup [%s]: %w", path, err)
+	}
+	j.records = append(j.records, journalRecord{op: op, path: path, backup: backup})
+	return nil
+}
+
+// Rollback restores the recorded changes in reverse order and removes the backups.
+func (j *Journal) Rollback() error {
+	if j == nil {
+		return nil
+	}
+
+	j.mx.Lock()
+	defer j.mx.Unlock()
+
+	var errs []error
+	for i := len(j.records) - 1; i >= 0; i-- {
+		record := j.records[i]
+		if err := os.RemoveAll(record.path); err != nil {
+			errs = append(errs, xerrors.Errorf("remove [%s]: %w", record.path, err))
+			continue
+		}
+		if record.op == journalOpCreate {
+			j.logger.Infof("rollback: remove: %s", record.path)
+			continue
+		}
+		if err := copyPath(record.backup, record.path); err != nil {
+			errs = append(errs, xerrors.Errorf("restore [%s]: %w", record.path, err))
+			continue
+		}
+		j.logger.Infof("rollback: restore: %s", record.path)
+	}
+	j.records = nil
+
+	if err := j.removeBackups(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return xerrors.Errorf("journal: rollback: %w", err)
+	}
+	return nil
+}
+
+// Commit forgets the recorded changes and removes the backups.
+func (j *Journal) Commit() error {
+	if j == nil {
+		return nil
+	}
+
+	j.mx.Lock()
+	defer j.mx.Unlock()
+	j.records = nil
+	return j.removeBackups()
+}
+
+func (j *Journal) removeBackups() error {
+	if j.backupDir == entity.Empty {
+		return nil
+	}
+	if err := os.RemoveAll(j.backupDir); err != nil {
+		return xerrors.Errorf("journal: remove backups [%s]: %w", j.backupDir, err)
+	}
+	j.backupDir = entity.Empty
+	return nil
+}
+
+// AtomicExecutor executes the executor and rolls back the journal's changes when the execution fails.
+type AtomicExecutor struct {
+	journal  *Journal
+	executor entity.Executor
+}
+
+func NewAtomicExecutor(journal *Journal, executor entity.Executor) *AtomicExecutor {
+	return &AtomicExecutor{
+		journal:  journal,
+		executor: executor,
+	}
+}
+
+func (e *AtomicExecutor) Exec() error {
+	err := e.executor.Exec()
+	if err == nil {
+		return e.journal.Commit()
+	}
+	if rollbackErr := e.journal.Rollback(); rollbackErr != nil {
+		return xerrors.Errorf("%v: %w", rollbackErr, err)
+	}
+	return err
+}
+
+// notExistingDirs returns the not existing dir and its not existing parents (from the dir to the root).
+func notExistingDirs(dir string) ([]string, error) {
+	var dirs []string
+	for path := filepath.Clean(dir); ; path = filepath.Dir(path) {
+		if _, err := os.Lstat(path); err == nil {
+			break
+		} else if !os.IsNotExist(err) {
+			return nil, xerrors.Errorf("stat [%s]: %w", path, err)
+		}
+		dirs = append(dirs, path)
+		if parent := filepath.Dir(path); parent == path {
+			break
+		}
+	}
+	return dirs, nil
+}
+
+// journalRemoveAllFn returns [os.RemoveAll], which records the removed path to the journal.
+func journalRemoveAllFn(journal *Journal) func(path string) error {
+	return func(path string) error {
+		if err := journal.Remove(path); err != nil {
+			return err
+		}
+		return os.RemoveAll(path)
+	}
+}
+
+// journalRenameFn returns [os.Rename], which records the new path and the removed old path to the journal.
+func journalRenameFn(journal *Journal) func(oldPath, newPath string) error {
+	return func(oldPath, newPath string) error {
+		if err := journal.Write(newPath); err != nil {
+			return err
+		}
+		if err := journal.Remove(oldPath); err != nil {
+			return err
+		}
+		return os.Rename(oldPath, newPath)
+	}
+}
+
+// copyPath copies the file, the symlink or the directory tree with the permissions.
+func copyPath(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			if err = os.MkdirAll(target, info.Mode().Perm()); err != nil {
+				return err
+			}
+			return os.Chmod(target, info.Mode().Perm())
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			return copyFile(path, target, info.Mode().Perm())
+		}
+	})
+}
+
+func copyFile(src, dst string, mode fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	if err = os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err = out.Close(); err != nil {
+		return err
+	}
+	// the mode of the created file is masked by umask
+	return os.Chmod(dst, mode)
+}
@@ -0,0 +1,108 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+func Test_Journal(t *testing.T) {
+	SkipSLowTest(t)
+
+	const (
+		someDir  = "some_dir"
+		someFile = "file_name.txt"
+	)
+
+	var (
+		oldData    = []byte("old data")
+		newData    = []byte("new data")
+		mockLogger = MockLogger{
+			infof: func(format string, args ...any) {},
+		}
+	)
+
+	t.Run("success_rollback_created_overwritten_and_removed", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a           = assert.New(t)
+				journal     = NewJournal(mockLogger)
+				createdDir  = filepath.Join(tmpDir, someDir, "a", "b")
+				overwritten = filepath.Join(tmpDir, someFile)
+				removed     = filepath.Join(tmpDir, "removed", someFile)
+			)
+			a.NoError(os.WriteFile(overwritten, oldData, 0o600))
+			a.NoError(os.MkdirAll(filepath.Dir(removed), os.ModePerm))
+			a.NoError(os.WriteFile(removed, oldData, 0o640))
+
+			_, err := NewMkdirAllStrategy(0, nil, journal, mockLogger).Apply(createdDir)
+			a.NoError(err)
+			a.DirExists(createdDir)
+
+			a.NoError(journal.Write(overwritten))
+			a.NoError(os.WriteFile(overwritten, newData, os.ModePerm))
+
+			a.NoError(NewRmAllStrategy(journal, mockLogger).Apply(filepath.Dir(removed)))
+			a.NoFileExists(removed)
+
+			a.NoError(journal.Rollback())
+			a.NoDirExists(filepath.Join(tmpDir, someDir))
+			AssertFileDataEqual(t, overwritten, oldData)
+			AssertFileDataEqual(t, removed, oldData)
+
+			info, err := os.Stat(removed)
+			a.NoError(err)
+			a.Equal(os.FileMode(0o640), info.Mode().Perm())
+		})
+	})
+	t.Run("success_commit_keep_changes", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a       = assert.New(t)
+				journal = NewJournal(mockLogger)
+				path    = filepath.Join(tmpDir, someFile)
+			)
+			a.NoError(os.WriteFile(path, oldData, os.ModePerm))
+			a.NoError(journal.Write(path))
+			backupDir := journal.backupDir
+			a.DirExists(backupDir)
+			a.NoError(os.WriteFile(path, newData, os.ModePerm))
+
+			a.NoError(journal.Commit())
+			a.NoDirExists(backupDir)
+			a.NoError(journal.Rollback())
+			AssertFileDataEqual(t, path, newData)
+		})
+	})
+	t.Run("success_nil_journal", func(t *testing.T) {
+		var journal *Journal
+		assert.NoError(t, journal.Mkdir(someDir))
+		assert.NoError(t, journal.Write(someFile))
+		assert.NoError(t, journal.Remove(someFile))
+		assert.NoError(t, journal.Rollback())
+		assert.NoError(t, journal.Commit())
+	})
+	t.Run("error_atomic_executor_rollback_on_failure", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a       = assert.New(t)
+				journal = NewJournal(mockLogger)
+				path    = filepath.Join(tmpDir, someDir, someFile)
+				expErr  = fmt.Errorf("some error")
+			)
+			executor := NewAtomicExecutor(journal, NewChain([]entity.Executor{
+				NewDirExecutor([]string{filepath.Dir(path)}, []entity.DirStrategy{NewMkdirAllStrategy(0, nil, journal, mockLogger)}),
+				mockParallelExecutor{exec: func(_ context.Context) error { return expErr }},
+			}))
+			err := executor.Exec()
+			a.ErrorIs(err, expErr)
+			a.NoDirExists(filepath.Dir(path))
+		})
+	})
+}
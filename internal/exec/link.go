@@ -0,0 +1,194 @@
+package exec
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+// readLinkFS is the file system, which returns the targets of the symbolic links (as [os.DirFS]).
+type readLinkFS interface {
+	fs.FS
+	ReadLink(name string) (string, error)
+}
+
+type LinksExecutor struct {
+	links      []entity.Link
+	strategies []entity.LinkStrategy
+}
+
+func NewLinksExecutor(links []entity.Link, strategies []entity.LinkStrategy) *LinksExecutor {
+	return &LinksExecutor{
+		links:      links,
+		strategies: strategies,
+	}
+}
+
+func (e *LinksExecutor) Exec() error {
+	for _, link := range e.links {
+		var err error
+		for _, strategy := range e.strategies {
+			link, err = strategy.Apply(link)
+			if err != nil {
+				return xerrors.Errorf("execute link: process link [%s]: %w", link.Path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// LinkStrategy creates the symbolic link (the target is stored as is) or the hard link
+// (the target is relative to the working directory). The link is recorded to the manifest
+// when its path does not exist or when it is already recorded.
+type LinkStrategy struct {
+	dirMode  os.FileMode
+	policy   *OnExistsPolicy
+	journal  *Journal
+	recorder *ManifestRecorder
+	logger   entity.Logger
+}
+
+func NewLinkStrategy(
+	dirMode os.FileMode,
+	policy *OnExistsPolicy,
+	journal *Journal,
+	recorder *ManifestRecorder,
+	logger entity.Logger) *LinkStrategy {
+	return &LinkStrategy{
+		dirMode:  dirMode,
+		policy:   policy,
+		journal:  journal,
+		recorder: recorder,
+		logger:   logger,
+	}
+}
+
+func (s *LinkStrategy) Apply(link entity.Link) (entity.Link, error) {
+	path := link.Path
+	info, err := os.Lstat(path)
+	switch {
+	case os.IsNotExist(err):
+		if err = s.create(link); err != nil {
+			return link, err
+		}
+		s.recorder.Link(link)
+		return link, nil
+	case err != nil:
+		return link, xerrors.Errorf("create link: stat [%s]: %w", path, err)
+	}
+
+	if sameLink(link, info) {
+		s.logger.Infof("link exists: %s", path)
+		return link, nil
+	}
+	if info.IsDir() {
+		return link, xerrors.Errorf("create link: path is a directory [%s]", path)
+	}
+
+	policy, err := s.policy.Resolve(path, link.OnExists)
+	if err != nil {
+		return link, xerrors.Errorf("create link: %w", err)
+	}
+	switch policy {
+	case entity.OnExistsSkip:
+		s.logger.Infof("link path exists, skip: %s", path)
+		return link, nil
+	case entity.OnExistsError:
+		return link, xerrors.Errorf("create link: path exists [%s]", path)
+	case entity.OnExistsOverwrite:
+		if err = s.journal.Write(path); err != nil {
+			return link, xerrors.Errorf("create link: %w", err)
+		}
+		if err = os.Remove(path); err != nil {
+			return link, xerrors.Errorf("create link: remove existing [%s]: %w", path, err)
+		}
+	case entity.OnExistsBackup:
+		backupPath := path + backupFileExt
+		if err = s.journal.Write(path); err != nil {
+			return link, xerrors.Errorf("create link: %w", err)
+		}
+		if err = s.journal.Write(backupPath); err != nil {
+			return link, xerrors.Errorf("create link: %w", err)
+		}
+		if err = os.Rename(path, backupPath); err != nil {
+			return link, xerrors.Errorf("create link: backup [%s]: %w", path, err)
+		}
+		s.logger.Infof("link path backup saved: %s", backupPath)
+	default:
+		return link, xerrors.Errorf("create link: `on_exists` policy is not supported by links: %v", policy)
+	}
+	if err = s.create(link); err != nil {
+		return link, err
+	}
+	if s.recorder.Tracked(path) {
+		s.recorder.Link(link)
+	}
+	return link, nil
+}
+
+func (s *LinkStrategy) create(link entity.Link) error {
+	path := link.Path
+	if err := s.journal.Write(path); err != nil {
+		return xerrors.Errorf("create link: %w", err)
+	}
+	if err := s.recorder.Dir(filepath.Dir(path)); err != nil {
+		return xerrors.Errorf("create link: %w", err)
+	}
+	if err := mkdirAll(filepath.Dir(path), s.dirMode); err != nil {
+		return xerrors.Errorf("create link: create dir [%s]: %w", filepath.Dir(path), err)
+	}
+
+	if link.Hard {
+		if err := os.Link(link.Target, path); err != nil {
+			return xerrors.Errorf("create hard link [%s]: %w", path, err)
+		}
+		s.logger.Infof("hard link created: %s -> %s", path, link.Target)
+		return nil
+	}
+	if err := os.Symlink(link.Target, path); err != nil {
+		return xerrors.Errorf("create link [%s]: %w", path, err)
+	}
+	s.logger.Infof("link created: %s -> %s", path, link.Target)
+	return nil
+}
+
+// sameLink reports whether the existing path is the link to the target.
+func sameLink(link entity.Link, info fs.FileInfo) bool {
+	if !link.Hard {
+		if info.Mode()&fs.ModeSymlink == 0 {
+			return false
+		}
+		target, err := os.Readlink(link.Path)
+		return err == nil && target == link.Target
+	}
+	targetInfo, err := os.Stat(link.Target)
+	return err == nil && os.SameFile(info, targetInfo)
+}
+
+type DryRunLinkStrategy struct {
+	logger entity.Logger
+}
+
+func NewDryRunLinkStrategy(logger entity.Logger) *DryRunLinkStrategy {
+	return &DryRunLinkStrategy{
+		logger: logger,
+	}
+}
+
+func (s *DryRunLinkStrategy) Apply(link entity.Link) (entity.Link, error) {
+	linkDir := filepath.Dir(link.Path)
+	if _, err := os.Stat(linkDir); os.IsNotExist(err) {
+		s.logger.Infof("create link: create dir [%s] to store link [%s]", linkDir, filepath.Base(link.Path))
+	}
+
+	if link.Hard {
+		s.logger.Infof("hard link created [path: %s]: %s", link.Path, link.Target)
+		return link, nil
+	}
+	s.logger.Infof("link created [path: %s]: %s", link.Path, link.Target)
+	return link, nil
+}
@@ -0,0 +1,142 @@
+package exec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+func Test_LinkStrategy(t *testing.T) {
+	SkipSLowTest(t)
+
+	const (
+		someFile = "some_file.txt"
+		someLink = "dir/some_link.txt"
+	)
+
+	var (
+		data       = []byte("data")
+		oldData    = []byte("old")
+		mockLogger = MockLogger{
+			infof: func(format string, args ...any) {},
+		}
+	)
+
+	apply := func(policy *OnExistsPolicy, link entity.Link) error {
+		_, err := NewLinkStrategy(0, policy, nil, nil, mockLogger).Apply(link)
+		return err
+	}
+
+	t.Run("success_symlink", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a    = assert.New(t)
+				path = filepath.Join(tmpDir, someLink)
+				link = entity.Link{Path: path, Target: filepath.Join("..", someFile)}
+			)
+			CreateFile(t, filepath.Join(tmpDir, someFile), data)
+
+			a.NoError(apply(nil, link))
+			target, err := os.Readlink(path)
+			a.NoError(err)
+			a.Equal(link.Target, target)
+			AssertFileDataEqual(t, path, data)
+
+			// the same link is not recreated
+			a.NoError(apply(NewOnExistsPolicy(entity.OnExistsError, nil), link))
+		})
+	})
+	t.Run("success_hard_link", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a      = assert.New(t)
+				path   = filepath.Join(tmpDir, someLink)
+				target = filepath.Join(tmpDir, someFile)
+				link   = entity.Link{Path: path, Target: target, Hard: true}
+			)
+			CreateFile(t, target, data)
+
+			a.NoError(apply(nil, link))
+			info, err := os.Lstat(path)
+			a.NoError(err)
+			a.Zero(info.Mode() & os.ModeSymlink)
+			targetInfo, err := os.Stat(target)
+			a.NoError(err)
+			a.True(os.SameFile(info, targetInfo))
+
+			a.NoError(apply(NewOnExistsPolicy(entity.OnExistsError, nil), link))
+		})
+	})
+	t.Run("success_overwrite_existing_file", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a    = assert.New(t)
+				path = filepath.Join(tmpDir, someLink)
+			)
+			CreateFile(t, filepath.Join(tmpDir, someFile), data)
+			CreateFile(t, path, oldData)
+
+			a.NoError(apply(nil, entity.Link{Path: path, Target: filepath.Join("..", someFile)}))
+			AssertFileDataEqual(t, path, data)
+		})
+	})
+	t.Run("success_skip", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a    = assert.New(t)
+				path = filepath.Join(tmpDir, someLink)
+			)
+			CreateFile(t, path, oldData)
+
+			a.NoError(apply(nil, entity.Link{Path: path, Target: someFile, OnExists: entity.OnExistsSkip}))
+			AssertFileDataEqual(t, path, oldData)
+		})
+	})
+	t.Run("success_backup", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a    = assert.New(t)
+				path = filepath.Join(tmpDir, someLink)
+			)
+			CreateFile(t, filepath.Join(tmpDir, someFile), data)
+			CreateFile(t, path, oldData)
+
+			a.NoError(apply(nil, entity.Link{Path: path, Target: filepath.Join("..", someFile), OnExists: entity.OnExistsBackup}))
+			AssertFileDataEqual(t, path, data)
+			AssertFileDataEqual(t, path+backupFileExt, oldData)
+		})
+	})
+	t.Run("error_path_exists", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			path := filepath.Join(tmpDir, someLink)
+			CreateFile(t, path, oldData)
+
+			err := apply(NewOnExistsPolicy(entity.OnExistsError, nil), entity.Link{Path: path, Target: someFile})
+			assert.Error(t, err)
+			AssertFileDataEqual(t, path, oldData)
+		})
+	})
+	t.Run("error_append_not_supported", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			path := filepath.Join(tmpDir, someLink)
+			CreateFile(t, path, oldData)
+
+			err := apply(nil, entity.Link{Path: path, Target: someFile, OnExists: entity.OnExistsAppend})
+			assert.Error(t, err)
+		})
+	})
+	t.Run("error_path_is_dir", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			path := filepath.Join(tmpDir, someLink)
+			assert.NoError(t, os.MkdirAll(path, os.ModePerm))
+
+			err := apply(nil, entity.Link{Path: path, Target: someFile})
+			assert.Error(t, err)
+			assert.DirExists(t, path)
+		})
+	})
+}
@@ -0,0 +1,487 @@
+package exec
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+// ManifestPath is the path of the generation manifest (relative to the application working directory).
+var ManifestPath = filepath.Join(".progen", "manifest.json")
+
+// manifestBaseDir is the dir of the rendered files (near the manifest), which are the base of the update.
+const manifestBaseDir = "base"
+
+// Drift statuses of the manifest's entries.
+const (
+	DriftMissing  = "missing"
+	DriftModified = "modified"
+)
+
+// ManifestEntry is the dir, the file or the link created by the action.
+type ManifestEntry struct {
+	Path   string `json:"path"`
+	Action string `json:"action,omitempty"`
+	Source string `json:"source,omitempty"`
+	SHA256 string `json:"sha256,omitempty"`
+	Target string `json:"target,omitempty"`
+	Hard   bool   `json:"hard,omitempty"`
+}
+
+// Drift is the difference between the manifest's entry and the working tree.
+type Drift struct {
+	Path   string
+	Status string
+}
+
+// Manifest lists the dirs, the files (with SHA-256 of the written content) and the links created by the actions.
+// The rendered content is stored as the base of the update. Methods of the nil Manifest do nothing.
+type Manifest struct {
+	mx    sync.Mutex
+	dirs  map[string]ManifestEntry
+	files map[string]ManifestEntry
+	links map[string]ManifestEntry
+	data  map[string][]byte
+	// renders are the rendered data of the files (before the merge), which are stored when the files are written
+	renders map[string][]byte
+}
+
+type manifestJSON struct {
+	Dirs  []ManifestEntry `json:"dirs"`
+	Files []ManifestEntry `json:"files"`
+	Links []ManifestEntry `json:"links,omitempty"`
+}
+
+func NewManifest() *Manifest {
+	return &Manifest{
+		dirs:    make(map[string]ManifestEntry),
+		files:   make(map[string]ManifestEntry),
+		links:   make(map[string]ManifestEntry),
+		data:    make(map[string][]byte),
+		renders: make(map[string][]byte),
+	}
+}
+
+// ManifestBasePath returns the path of the stored render of the file,
+// returns false when the file is outside the working directory.
+func ManifestBasePath(manifestPath, path string) (string, bool) {
+	path = filepath.Clean(path)
+	if filepath.IsAbs(path) || path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator)) {
+		return entity.Empty, false
+	}
+	return filepath.Join(filepath.Dir(manifestPath), manifestBaseDir, path), true
+}
+
+// ReadManifest reads the manifest file.
+func ReadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, xerrors.Errorf("read manifest: %w", err)
+	}
+	var raw manifestJSON
+	if err = json.Unmarshal(data, &raw); err != nil {
+		return nil, xerrors.Errorf("read manifest [%s]: %w", path, err)
+	}
+
+	m := NewManifest()
+	for _, dir := range raw.Dirs {
+		m.dirs[dir.Path] = dir
+	}
+	for _, file := range raw.Files {
+		m.files[file.Path] = file
+	}
+	for _, link := range raw.Links {
+		m.links[link.Path] = link
+	}
+	return m, nil
+}
+
+// OpenManifest reads the existing manifest file (the files of the previous runs stay recorded when they
+// are rewritten) or creates the empty manifest when the file does not exist.
+func OpenManifest(path string) (*Manifest, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return NewManifest(), nil
+	}
+	return ReadManifest(path)
+}
+
+// Recorder returns the recorder of the action's dirs and files.
+func (m *Manifest) Recorder(action string) *ManifestRecorder {
+	if m == nil {
+		return nil
+	}
+	return &ManifestRecorder{
+		manifest: m,
+		action:   action,
+	}
+}
+
+// Len returns the number of the manifest's entries.
+func (m *Manifest) Len() int {
+	if m == nil {
+		return 0
+	}
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	return len(m.dirs) + len(m.files) + len(m.links)
+}
+
+// Save writes the manifest merged with the existing manifest file (the entries are replaced by the paths)
+// and stores the rendered content of the files.
+func (m *Manifest) Save(path string) error {
+	if m.Len() == 0 {
+		return nil
+	}
+
+	saved := NewManifest()
+	if _, err := os.Stat(path); err == nil {
+		if saved, err = ReadManifest(path); err != nil {
+			return xerrors.Errorf("save manifest: %w", err)
+		}
+	}
+
+	m.mx.Lock()
+	for p, dir := range m.dirs {
+		saved.dirs[p] = dir
+	}
+	for p, file := range m.files {
+		saved.files[p] = file
+		delete(saved.links, p)
+	}
+	for p, link := range m.links {
+		saved.links[p] = link
+		delete(saved.files, p)
+	}
+	data := make(map[string][]byte, len(m.data))
+	for p, d := range m.data {
+		data[p] = d
+	}
+	m.mx.Unlock()
+
+	for p, d := range data {
+		basePath, ok := ManifestBasePath(path, p)
+		if !ok {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(basePath), os.ModePerm); err != nil {
+			return xerrors.Errorf("save manifest: create base dir: %w", err)
+		}
+		if err := os.WriteFile(basePath, d, 0o644); err != nil {
+			return xerrors.Errorf("save manifest: write base [%s]: %w", basePath, err)
+		}
+	}
+	return saved.write(path)
+}
+
+// Verify compares the manifest's entries with the working tree.
+func (m *Manifest) Verify() ([]Drift, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	var drifts []Drift
+	for _, dir := range sortedEntries(m.dirs) {
+		if _, err := os.Stat(dir.Path); os.IsNotExist(err) {
+			drifts = append(drifts, Drift{Path: dir.Path, Status: DriftMissing})
+		} else if err != nil {
+			return nil, xerrors.Errorf("verify manifest: stat [%s]: %w", dir.Path, err)
+		}
+	}
+	for _, file := range sortedEntries(m.files) {
+		data, err := os.ReadFile(file.Path)
+		switch {
+		case os.IsNotExist(err):
+			drifts = append(drifts, Drift{Path: file.Path, Status: DriftMissing})
+		case err != nil:
+			return nil, xerrors.Errorf("verify manifest: read [%s]: %w", file.Path, err)
+		case checksum(data) != file.SHA256:
+			drifts = append(drifts, Drift{Path: file.Path, Status: DriftModified})
+		}
+	}
+	for _, link := range sortedEntries(m.links) {
+		info, err := os.Lstat(link.Path)
+		switch {
+		case os.IsNotExist(err):
+			drifts = append(drifts, Drift{Path: link.Path, Status: DriftMissing})
+		case err != nil:
+			return nil, xerrors.Errorf("verify manifest: stat [%s]: %w", link.Path, err)
+		case !sameLink(entity.Link{Path: link.Path, Target: link.Target, Hard: link.Hard}, info):
+			drifts = append(drifts, Drift{Path: link.Path, Status: DriftModified})
+		}
+	}
+	return drifts, nil
+}
+
+// Clean removes the manifest's files and links, which are not modified (or all of them when `force` is true),
+// and the empty dirs. The kept entries are written back to the manifest file,
+// the manifest file is removed when all entries are removed. Clean returns the skipped modified files.
+func (m *Manifest) Clean(path string, force bool, logger entity.Logger) ([]Drift, error) {
+	drifts, err := m.Verify()
+	if err != nil {
+		return nil, xerrors.Errorf("clean: %w", err)
+	}
+
+	var skipped []Drift
+	m.mx.Lock()
+	for _, drift := range drifts {
+		switch {
+		case drift.Status == DriftMissing:
+			delete(m.files, drift.Path)
+			delete(m.links, drift.Path)
+			delete(m.dirs, drift.Path)
+		case !force:
+			skipped = append(skipped, drift)
+		}
+	}
+
+	for _, file := range sortedEntries(m.files) {
+		if !force && containsDrift(drifts, file.Path) {
+			continue
+		}
+		if err = os.Remove(file.Path); err != nil {
+			m.mx.Unlock()
+			return nil, xerrors.Errorf("clean: remove file [%s]: %w", file.Path, err)
+		}
+		delete(m.files, file.Path)
+		if basePath, ok := ManifestBasePath(path, file.Path); ok {
+			_ = os.Remove(basePath)
+		}
+		logger.Infof("clean: file removed: %s", file.Path)
+	}
+
+	for _, link := range sortedEntries(m.links) {
+		if !force && containsDrift(drifts, link.Path) {
+			continue
+		}
+		if err = os.Remove(link.Path); err != nil {
+			m.mx.Unlock()
+			return nil, xerrors.Errorf("clean: remove link [%s]: %w", link.Path, err)
+		}
+		delete(m.links, link.Path)
+		logger.Infof("clean: link removed: %s", link.Path)
+	}
+
+	dirs := sortedEntries(m.dirs)
+	// the nested dirs are removed before the parents
+	for i := len(dirs) - 1; i >= 0; i-- {
+		dir := dirs[i].Path
+		if removed, err := removeEmptyDirs(dir); err != nil {
+			m.mx.Unlock()
+			return nil, xerrors.Errorf("clean: %w", err)
+		} else if !removed {
+			logger.Infof("clean: skip not empty dir: %s", dir)
+			continue
+		}
+		delete(m.dirs, dir)
+		logger.Infof("clean: dir removed: %s", dir)
+	}
+	m.mx.Unlock()
+
+	if m.Len() > 0 {
+		return skipped, m.write(path)
+	}
+	if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, xerrors.Errorf("clean: remove manifest: %w", err)
+	}
+	if err = os.RemoveAll(filepath.Join(filepath.Dir(path), manifestBaseDir)); err != nil {
+		return nil, xerrors.Errorf("clean: remove base: %w", err)
+	}
+	_, _ = removeEmptyDirs(filepath.Dir(path))
+	return skipped, nil
+}
+
+func (m *Manifest) write(path string) error {
+	m.mx.Lock()
+	raw := manifestJSON{
+		Dirs:  sortedEntries(m.dirs),
+		Files: sortedEntries(m.files),
+		Links: sortedEntries(m.links),
+	}
+	m.mx.Unlock()
+
+	data, err := json.MarshalIndent(raw, entity.Empty, "  ")
+	if err != nil {
+		return xerrors.Errorf("write manifest: %w", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return xerrors.Errorf("write manifest: create dir: %w", err)
+	}
+	if err = os.WriteFile(path, append(data, entity.NewLine...), 0o644); err != nil {
+		return xerrors.Errorf("write manifest [%s]: %w", path, err)
+	}
+	return nil
+}
+
+// ManifestRecorder records the dirs, the files and the links of the action to the manifest.
+// Methods of the nil ManifestRecorder do nothing.
+type ManifestRecorder struct {
+	manifest *Manifest
+	action   string
+}
+
+// Dir records the dir and its parents, which are going to be created.
+func (r *ManifestRecorder) Dir(path string) error {
+	if r == nil {
+		return nil
+	}
+	dirs, err := notExistingDirs(path)
+	if err != nil {
+		return xerrors.Errorf("manifest: %w", err)
+	}
+
+	r.manifest.mx.Lock()
+	defer r.manifest.mx.Unlock()
+	for _, dir := range dirs {
+		r.manifest.dirs[dir] = ManifestEntry{Path: dir, Action: r.action}
+	}
+	return nil
+}
+
+// Render keeps the rendered data of the file (the base of the update), which is stored when the file is written.
+func (r *ManifestRecorder) Render(path string, data []byte) {
+	if r == nil {
+		return
+	}
+	r.manifest.mx.Lock()
+	defer r.manifest.mx.Unlock()
+	r.manifest.renders[filepath.Clean(path)] = data
+}
+
+// Tracked reports whether the file or the link is recorded by the manifest (created by the current or the previous run).
+func (r *ManifestRecorder) Tracked(path string) bool {
+	if r == nil {
+		return false
+	}
+	r.manifest.mx.Lock()
+	defer r.manifest.mx.Unlock()
+	path = filepath.Clean(path)
+	_, file := r.manifest.files[path]
+	_, link := r.manifest.links[path]
+	return file || link
+}
+
+// File records the written file with the checksum of the kept render (or the written data when the render is not
+// kept), the render is stored as the base of the update. The merged file with the kept modifications (or conflicts)
+// does not match the checksum, so it is reported as modified and it is not removed by [Manifest.Clean].
+func (r *ManifestRecorder) File(path, source string, data []byte) error {
+	if r == nil {
+		return nil
+	}
+
+	r.manifest.mx.Lock()
+	defer r.manifest.mx.Unlock()
+	path = filepath.Clean(path)
+	base, ok := r.manifest.renders[path]
+	if !ok {
+		base = data
+	}
+	delete(r.manifest.renders, path)
+	delete(r.manifest.links, path)
+	r.manifest.files[path] = ManifestEntry{Path: path, Action: r.action, Source: source, SHA256: checksum(base)}
+	r.manifest.data[path] = base
+	return nil
+}
+
+// Link records the created link with its target.
+func (r *ManifestRecorder) Link(link entity.Link) {
+	if r == nil {
+		return
+	}
+	r.manifest.mx.Lock()
+	defer r.manifest.mx.Unlock()
+	path := filepath.Clean(link.Path)
+	delete(r.manifest.files, path)
+	delete(r.manifest.data, path)
+	r.manifest.links[path] = ManifestEntry{Path: path, Action: r.action, Target: link.Target, Hard: link.Hard}
+}
+
+// ManifestDirStrategy records the dir, which is going to be created, to the manifest.
+type ManifestDirStrategy struct {
+	recorder *ManifestRecorder
+}
+
+func NewManifestDirStrategy(recorder *ManifestRecorder) *ManifestDirStrategy {
+	return &ManifestDirStrategy{
+		recorder: recorder,
+	}
+}
+
+func (s *ManifestDirStrategy) Apply(dir string) (string, error) {
+	if err := s.recorder.Dir(dir); err != nil {
+		return entity.Empty, err
+	}
+	return dir, nil
+}
+
+// ManifestFileStrategy keeps the rendered data of the file as the base of the update,
+// the file is recorded to the manifest when it is written by [SaveFileStrategy].
+type ManifestFileStrategy struct {
+	recorder *ManifestRecorder
+	// source overrides the file's source when it is not empty.
+	source string
+}
+
+func NewManifestFileStrategy(recorder *ManifestRecorder, source string) *ManifestFileStrategy {
+	return &ManifestFileStrategy{
+		recorder: recorder,
+		source:   source,
+	}
+}
+
+func (s *ManifestFileStrategy) Apply(file entity.DataFile) (entity.DataFile, error) {
+	if s.source != entity.Empty {
+		file.Source = s.source
+	}
+	s.recorder.Render(file.Path(), file.Data)
+	return file, nil
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func sortedEntries(entries map[string]ManifestEntry) []ManifestEntry {
+	res := make([]ManifestEntry, 0, len(entries))
+	for _, entry := range entries {
+		res = append(res, entry)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Path < res[j].Path
+	})
+	return res
+}
+
+func containsDrift(drifts []Drift, path string) bool {
+	for _, drift := range drifts {
+		if drift.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// removeEmptyDirs removes the dir when it is empty, returns false when the dir is not empty.
+func removeEmptyDirs(dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	switch {
+	case os.IsNotExist(err):
+		return true, nil
+	case err != nil:
+		return false, xerrors.Errorf("read dir [%s]: %w", dir, err)
+	case len(entries) > 0:
+		return false, nil
+	}
+	if err = os.Remove(dir); err != nil {
+		return false, xerrors.Errorf("remove dir [%s]: %w", dir, err)
+	}
+	return true, nil
+}
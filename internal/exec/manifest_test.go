@@ -0,0 +1,265 @@
+package exec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+func Test_Manifest(t *testing.T) {
+	SkipSLowTest(t)
+
+	const (
+		someAction  = "files"
+		linksAction = "links"
+		someDir     = "some_dir"
+		someFile    = "file_name.txt"
+	)
+
+	var (
+		someData   = []byte("some data")
+		mockLogger = MockLogger{
+			infof: func(format string, args ...any) {},
+		}
+	)
+
+	t.Run("success_save_and_verify", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a            = assert.New(t)
+				manifest     = NewManifest()
+				manifestPath = filepath.Join(tmpDir, ManifestPath)
+				dir          = filepath.Join(tmpDir, someDir, "a")
+				file         = filepath.Join(dir, someFile)
+			)
+
+			recorder := manifest.Recorder(someAction)
+			_, err := NewSaveFileStrategy(entity.FileModes{}, nil, nil, recorder, mockLogger).
+				Apply(entity.DataFile{FileInfo: entity.NewFileInfo(file), Data: someData, Source: entity.FileSourceData})
+			a.NoError(err)
+			a.NoError(manifest.Save(manifestPath))
+
+			saved, err := ReadManifest(manifestPath)
+			a.NoError(err)
+			a.Equal(3, saved.Len())
+			a.Equal(ManifestEntry{Path: file, Action: someAction, Source: entity.FileSourceData, SHA256: checksum(someData)}, saved.files[file])
+			a.Contains(saved.dirs, filepath.Join(tmpDir, someDir))
+
+			drifts, err := saved.Verify()
+			a.NoError(err)
+			a.Empty(drifts)
+
+			a.NoError(os.WriteFile(file, []byte("modified"), os.ModePerm))
+			drifts, err = saved.Verify()
+			a.NoError(err)
+			a.Equal([]Drift{{Path: file, Status: DriftModified}}, drifts)
+
+			a.NoError(os.RemoveAll(dir))
+			drifts, err = saved.Verify()
+			a.NoError(err)
+			a.Equal([]Drift{{Path: dir, Status: DriftMissing}, {Path: file, Status: DriftMissing}}, drifts)
+		})
+	})
+	t.Run("success_record_created_and_tracked_files", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a        = assert.New(t)
+				manifest = NewManifest()
+				recorder = manifest.Recorder(someAction)
+				existing = filepath.Join(tmpDir, "existing.txt")
+				created  = filepath.Join(tmpDir, "created.txt")
+				skipped  = filepath.Join(tmpDir, "skipped.txt")
+				strategy = NewSaveFileStrategy(entity.FileModes{}, nil, nil, recorder, mockLogger)
+			)
+			a.NoError(os.WriteFile(existing, []byte("user"), os.ModePerm))
+			a.NoError(os.WriteFile(skipped, []byte("user"), os.ModePerm))
+
+			for _, file := range []entity.DataFile{
+				{FileInfo: entity.NewFileInfo(existing), Data: someData},
+				{FileInfo: entity.NewFileInfo(skipped), Data: someData, OnExists: entity.OnExistsSkip},
+				{FileInfo: entity.NewFileInfo(created), Data: []byte("render")},
+			} {
+				file, err := NewManifestFileStrategy(recorder, entity.FileSourceFs).Apply(file)
+				a.NoError(err)
+				_, err = strategy.Apply(file)
+				a.NoError(err)
+			}
+			a.Equal(1, manifest.Len())
+			a.Equal(checksum([]byte("render")), manifest.files[created].SHA256)
+
+			// the tracked file is recorded with the checksum of the render, the render is kept as the base
+			file, err := NewManifestFileStrategy(recorder, entity.FileSourceFs).
+				Apply(entity.DataFile{FileInfo: entity.NewFileInfo(created), Data: someData})
+			a.NoError(err)
+			file.Data = []byte("merged")
+			_, err = strategy.Apply(file)
+			a.NoError(err)
+			a.Equal(1, manifest.Len())
+			a.Equal(ManifestEntry{Path: created, Action: someAction, Source: entity.FileSourceFs, SHA256: checksum(someData)}, manifest.files[created])
+			a.Equal(someData, manifest.data[created])
+		})
+	})
+	t.Run("success_clean_skip_modified", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a            = assert.New(t)
+				manifest     = NewManifest()
+				recorder     = manifest.Recorder(someAction)
+				manifestPath = filepath.Join(tmpDir, ManifestPath)
+				dir          = filepath.Join(tmpDir, someDir)
+				fileA        = filepath.Join(dir, "a.txt")
+				fileB        = filepath.Join(dir, "b.txt")
+			)
+			a.NoError(recorder.Dir(dir))
+			for _, file := range []string{fileA, fileB} {
+				a.NoError(recorder.File(file, entity.FileSourceData, someData))
+				a.NoError(os.MkdirAll(dir, os.ModePerm))
+				a.NoError(os.WriteFile(file, someData, os.ModePerm))
+			}
+			a.NoError(manifest.Save(manifestPath))
+			a.NoError(os.WriteFile(fileB, []byte("modified"), os.ModePerm))
+
+			saved, err := ReadManifest(manifestPath)
+			a.NoError(err)
+			skipped, err := saved.Clean(manifestPath, false, mockLogger)
+			a.NoError(err)
+			a.Equal([]Drift{{Path: fileB, Status: DriftModified}}, skipped)
+			a.NoFileExists(fileA)
+			a.FileExists(fileB)
+			a.FileExists(manifestPath)
+
+			saved, err = ReadManifest(manifestPath)
+			a.NoError(err)
+			skipped, err = saved.Clean(manifestPath, true, mockLogger)
+			a.NoError(err)
+			a.Empty(skipped)
+			a.NoDirExists(dir)
+			a.NoFileExists(manifestPath)
+		})
+	})
+	t.Run("success_clean_keep_updated_modifications", func(t *testing.T) {
+		// the base of the update is stored only for the relative paths
+		tmpDir, err := os.MkdirTemp(entity.Dot, "manifest_update_")
+		assert.NoError(t, err)
+		defer func() {
+			assert.NoError(t, os.RemoveAll(tmpDir))
+		}()
+
+		var (
+			a            = assert.New(t)
+			manifestPath = filepath.Join(tmpDir, ManifestPath)
+			modified     = filepath.Join(tmpDir, "modified.txt")
+			conflicted   = filepath.Join(tmpDir, "conflicted.txt")
+			generated    = filepath.Join(tmpDir, "generated.txt")
+		)
+
+		run := func(data map[string]string) {
+			manifest, err := OpenManifest(manifestPath)
+			a.NoError(err)
+			var (
+				recorder   = manifest.Recorder(someAction)
+				strategies = []entity.FileStrategy{
+					NewManifestFileStrategy(recorder, entity.Empty),
+					NewUpdateFileStrategy(NewUpdater(manifestPath, false, nil, mockLogger)),
+					NewSaveFileStrategy(entity.FileModes{}, nil, nil, recorder, mockLogger),
+				}
+			)
+			for path, d := range data {
+				file := entity.DataFile{FileInfo: entity.NewFileInfo(path), Data: []byte(d), Source: entity.FileSourceData}
+				for _, strategy := range strategies {
+					file, err = strategy.Apply(file)
+					a.NoError(err)
+				}
+			}
+			a.NoError(manifest.Save(manifestPath))
+		}
+
+		run(map[string]string{modified: "a\nb\nc\n", conflicted: "a\n", generated: "a\n"})
+		a.NoError(os.WriteFile(modified, []byte("a user\nb\nc\n"), os.ModePerm))
+		a.NoError(os.WriteFile(conflicted, []byte("user\n"), os.ModePerm))
+
+		// update: the merged file keeps the modifications, the conflict markers are written
+		run(map[string]string{modified: "a\nb\nc tmpl\n", conflicted: "tmpl\n", generated: "b\n"})
+		AssertFileDataEqual(t, modified, []byte("a user\nb\nc tmpl\n"))
+		AssertFileDataEqual(t, generated, []byte("b\n"))
+
+		saved, err := ReadManifest(manifestPath)
+		a.NoError(err)
+		drifts, err := saved.Verify()
+		a.NoError(err)
+		a.Equal([]Drift{{Path: conflicted, Status: DriftModified}, {Path: modified, Status: DriftModified}}, drifts)
+
+		skipped, err := saved.Clean(manifestPath, false, mockLogger)
+		a.NoError(err)
+		a.Equal(drifts, skipped)
+		a.FileExists(modified)
+		a.FileExists(conflicted)
+		a.NoFileExists(generated)
+	})
+	t.Run("success_record_verify_and_clean_links", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a            = assert.New(t)
+				manifest     = NewManifest()
+				manifestPath = filepath.Join(tmpDir, ManifestPath)
+				dir          = filepath.Join(tmpDir, someDir)
+				target       = filepath.Join(tmpDir, someFile)
+				existing     = filepath.Join(tmpDir, "existing.txt")
+				symlink      = entity.Link{Path: filepath.Join(dir, "sym.txt"), Target: target}
+				hardLink     = entity.Link{Path: filepath.Join(dir, "hard.txt"), Target: target, Hard: true}
+				strategy     = NewLinkStrategy(0, nil, nil, manifest.Recorder(linksAction), mockLogger)
+			)
+			a.NoError(os.WriteFile(target, someData, os.ModePerm))
+			a.NoError(os.WriteFile(existing, someData, os.ModePerm))
+
+			for _, link := range []entity.Link{symlink, hardLink, {Path: existing, Target: target}} {
+				_, err := strategy.Apply(link)
+				a.NoError(err)
+			}
+			a.NoError(manifest.Save(manifestPath))
+
+			saved, err := ReadManifest(manifestPath)
+			a.NoError(err)
+			// the overwritten existing file is not recorded
+			a.Equal(3, saved.Len())
+			a.Equal(ManifestEntry{Path: symlink.Path, Action: linksAction, Target: target}, saved.links[symlink.Path])
+			a.Equal(ManifestEntry{Path: hardLink.Path, Action: linksAction, Target: target, Hard: true}, saved.links[hardLink.Path])
+
+			drifts, err := saved.Verify()
+			a.NoError(err)
+			a.Empty(drifts)
+
+			a.NoError(os.Remove(symlink.Path))
+			a.NoError(os.Symlink(existing, symlink.Path))
+			drifts, err = saved.Verify()
+			a.NoError(err)
+			a.Equal([]Drift{{Path: symlink.Path, Status: DriftModified}}, drifts)
+
+			skipped, err := saved.Clean(manifestPath, false, mockLogger)
+			a.NoError(err)
+			a.Equal([]Drift{{Path: symlink.Path, Status: DriftModified}}, skipped)
+			a.NoFileExists(hardLink.Path)
+			a.FileExists(target)
+
+			skipped, err = saved.Clean(manifestPath, true, mockLogger)
+			a.NoError(err)
+			a.Empty(skipped)
+			a.NoDirExists(dir)
+			a.NoFileExists(manifestPath)
+			a.FileExists(existing)
+		})
+	})
+	t.Run("success_nil_manifest", func(t *testing.T) {
+		var manifest *Manifest
+		recorder := manifest.Recorder(someAction)
+		assert.Nil(t, recorder)
+		assert.NoError(t, recorder.Dir(someDir))
+		assert.NoError(t, recorder.File(someFile, entity.FileSourceData, someData))
+		assert.Equal(t, 0, manifest.Len())
+		assert.NoError(t, manifest.Save(someFile))
+	})
+}
@@ -0,0 +1,303 @@
+package exec
+
+import (
+	"bytes"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+// Conflict markers of the merged file.
+const (
+	conflictMarkerCurrent  = "<<<<<<< current"
+	conflictMarkerSep      = "======="
+	conflictMarkerTemplate = ">>>>>>> template"
+
+	rejectFileExt = ".rej"
+)
+
+// Updater merges the new render of the file with the current file on the disk, the previous render (stored by the
+// manifest) is the base of the three-way merge. Methods of the nil Updater do nothing.
+type Updater struct {
+	mx         sync.Mutex
+	reject     bool
+	conflicts  []string
+	journal    *Journal
+	logger     entity.Logger
+	basePathFn func(path string) (string, bool)
+}
+
+// NewUpdater creates the Updater, the conflicts are written as the conflict markers or
+// as `.rej` files with the new render when `reject` is true (`.rej` files are recorded to the journal).
+func NewUpdater(manifestPath string, reject bool, journal *Journal, logger entity.Logger) *Updater {
+	return &Updater{
+		reject:  reject,
+		journal: journal,
+		logger:  logger,
+		basePathFn: func(path string) (string, bool) {
+			return ManifestBasePath(manifestPath, path)
+		},
+	}
+}
+
+// Conflicts returns the paths of the files with the conflicts.
+func (u *Updater) Conflicts() []string {
+	if u == nil {
+		return nil
+	}
+	u.mx.Lock()
+	defer u.mx.Unlock()
+	return append([]string(nil), u.conflicts...)
+}
+
+// Merge returns the file with the merged data.
+func (u *Updater) Merge(file entity.DataFile) (entity.DataFile, error) {
+	if u == nil {
+		return file, nil
+	}
+
+	path := file.Path()
+	current, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return file, nil
+	case err != nil:
+		return file, xerrors.Errorf("update: read current [%s]: %w", path, err)
+	}
+
+	// the merged file replaces the current file regardless of the `on_exists` policy
+	file.OnExists = entity.OnExistsOverwrite
+	if bytes.Equal(current, file.Data) {
+		return file, nil
+	}
+
+	var (
+		base    []byte
+		hasBase bool
+	)
+	if basePath, ok := u.basePathFn(path); ok {
+		base, err = os.ReadFile(basePath)
+		switch {
+		case err == nil:
+			hasBase = true
+		case !os.IsNotExist(err):
+			return file, xerrors.Errorf("update: read base [%s]: %w", basePath, err)
+		}
+	}
+
+	switch {
+	case hasBase && bytes.Equal(current, base):
+		// the file is not modified: the new render is saved
+		u.logger.Infof("update: file is not modified: %s", path)
+		return file, nil
+	case hasBase && bytes.Equal(base, file.Data):
+		// the template is not changed: the modifications are kept
+		u.logger.Infof("update: template is not changed: %s", path)
+		file.Data = current
+		return file, nil
+	}
+
+	// the raw (binary) data is not merged by lines, the changed raw file is always the conflict
+	var merged string
+	if !file.Raw {
+		var conflict bool
+		merged, conflict = merge3(string(base), string(current), string(file.Data))
+		if !conflict {
+			u.logger.Infof("update: file merged: %s", path)
+			file.Data = []byte(merged)
+			return file, nil
+		}
+	}
+
+	u.mx.Lock()
+	u.conflicts = append(u.conflicts, path)
+	u.mx.Unlock()
+
+	if u.reject {
+		rejectPath := path + rejectFileExt
+		if err = u.journal.Write(rejectPath); err != nil {
+			return file, xerrors.Errorf("update: reject file [%s]: %w", rejectPath, err)
+		}
+		if err = os.WriteFile(rejectPath, file.Data, 0o644); err != nil {
+			return file, xerrors.Errorf("update: write reject file [%s]: %w", rejectPath, err)
+		}
+		u.logger.Infof("update: conflict, reject file saved: %s", rejectPath)
+		file.Data = current
+		return file, nil
+	}
+	if file.Raw {
+		u.logger.Infof("update: conflict, raw file is kept: %s", path)
+		file.Data = current
+		return file, nil
+	}
+	u.logger.Infof("update: conflict, markers are written: %s", path)
+	file.Data = []byte(merged)
+	return file, nil
+}
+
+// UpdateFileStrategy merges the file with the current file on the disk by the [Updater].
+type UpdateFileStrategy struct {
+	updater *Updater
+}
+
+func NewUpdateFileStrategy(updater *Updater) *UpdateFileStrategy {
+	return &UpdateFileStrategy{
+		updater: updater,
+	}
+}
+
+func (s *UpdateFileStrategy) Apply(file entity.DataFile) (entity.DataFile, error) {
+	return s.updater.Merge(file)
+}
+
+// merge3 merges the lines of the current and the next text with the common base (diff3),
+// the conflicting chunks are written with the conflict markers.
+func merge3(base, current, next string) (string, bool) {
+	var (
+		baseLines    = splitLines(base)
+		currentLines = splitLines(current)
+		nextLines    = splitLines(next)
+
+		currentMatch = lcsMatch(baseLines, currentLines)
+		nextMatch    = lcsMatch(baseLines, nextLines)
+
+		res      strings.Builder
+		conflict bool
+	)
+
+	// resolve writes the chunk between the stable lines
+	resolve := func(b, c, n []string) {
+		switch {
+		case slices.Equal(c, b):
+			writeLines(&res, n)
+		case slices.Equal(n, b), slices.Equal(c, n):
+			writeLines(&res, c)
+		default:
+			conflict = true
+			writeLines(&res, []string{conflictMarkerCurrent + entity.NewLine})
+			writeLines(&res, withNewLine(c))
+			writeLines(&res, []string{conflictMarkerSep + entity.NewLine})
+			writeLines(&res, withNewLine(n))
+			writeLines(&res, []string{conflictMarkerTemplate + entity.NewLine})
+		}
+	}
+
+	var b, c, n int
+	for i := range baseLines {
+		ci, cOk := currentMatch[i]
+		ni, nOk := nextMatch[i]
+		if !cOk || !nOk || ci < c || ni < n {
+			continue
+		}
+		// the base line is stable in both texts
+		resolve(baseLines[b:i], currentLines[c:ci], nextLines[n:ni])
+		writeLines(&res, []string{baseLines[i]})
+		b, c, n = i+1, ci+1, ni+1
+	}
+	resolve(baseLines[b:], currentLines[c:], nextLines[n:])
+	return res.String(), conflict
+}
+
+// lcsMatch returns the indexes of the lines of the longest common subsequence (`a` index to `b` index).
+// The subsequence is found by Hirschberg's algorithm, which uses the linear space.
+func lcsMatch(a, b []string) map[int]int {
+	match := make(map[int]int)
+	hirschberg(a, b, 0, 0, match)
+	return match
+}
+
+// hirschberg splits `a` in the middle and `b` at the point of the maximal sum of the subsequences of the halves,
+// the halves are matched recursively (`aOffset`, `bOffset` are the offsets of the halves).
+func hirschberg(a, b []string, aOffset, bOffset int, match map[int]int) {
+	// the common prefix and suffix are the part of the subsequence
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		match[aOffset] = bOffset
+		a, b = a[1:], b[1:]
+		aOffset++
+		bOffset++
+	}
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1] == b[len(b)-1] {
+		match[aOffset+len(a)-1] = bOffset + len(b) - 1
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+
+	switch {
+	case len(a) == 0 || len(b) == 0:
+		return
+	case len(a) == 1:
+		if j := slices.Index(b, a[0]); j >= 0 {
+			match[aOffset] = bOffset + j
+		}
+		return
+	}
+
+	var (
+		mid   = len(a) / 2
+		left  = lcsLengths(a[:mid], b, false)
+		right = lcsLengths(a[mid:], b, true)
+		split = 0
+	)
+	for j := range left {
+		if left[j]+right[len(b)-j] > left[split]+right[len(b)-split] {
+			split = j
+		}
+	}
+	hirschberg(a[:mid], b[:split], aOffset, bOffset, match)
+	hirschberg(a[mid:], b[split:], aOffset+mid, bOffset+split, match)
+}
+
+// lcsLengths returns the lengths of the longest common subsequences of `a` and the prefixes of `b`
+// (the suffixes when `reverse` is true), only two rows of the table are kept.
+func lcsLengths(a, b []string, reverse bool) []int {
+	at := func(s []string, i int) string {
+		if reverse {
+			return s[len(s)-1-i]
+		}
+		return s[i]
+	}
+
+	prev, cur := make([]int, len(b)+1), make([]int, len(b)+1)
+	for i := range a {
+		for j := range b {
+			if at(a, i) == at(b, j) {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+func splitLines(s string) []string {
+	if s == entity.Empty {
+		return nil
+	}
+	lines := strings.SplitAfter(s, entity.NewLine)
+	if lines[len(lines)-1] == entity.Empty {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// withNewLine adds the new line to the last line (the conflict markers start with the new line).
+func withNewLine(lines []string) []string {
+	if len(lines) == 0 || strings.HasSuffix(lines[len(lines)-1], entity.NewLine) {
+		return lines
+	}
+	res := append([]string(nil), lines...)
+	res[len(res)-1] += entity.NewLine
+	return res
+}
+
+func writeLines(w *strings.Builder, lines []string) {
+	for _, line := range lines {
+		w.WriteString(line)
+	}
+}
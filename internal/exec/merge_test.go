@@ -0,0 +1,164 @@
+package exec
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+func Test_merge3(t *testing.T) {
+	t.Parallel()
+
+	const (
+		base = "a\nb\nc\nd\n"
+	)
+
+	t.Run("success_merge_not_overlapping_changes", func(t *testing.T) {
+		merged, conflict := merge3(base, "a user\nb\nc\nd\n", "a\nb\nc\nd tmpl\ne\n")
+		assert.False(t, conflict)
+		assert.Equal(t, "a user\nb\nc\nd tmpl\ne\n", merged)
+	})
+	t.Run("success_merge_same_changes", func(t *testing.T) {
+		merged, conflict := merge3(base, "a\nb new\nc\nd\n", "a\nb new\nc\nd\n")
+		assert.False(t, conflict)
+		assert.Equal(t, "a\nb new\nc\nd\n", merged)
+	})
+	t.Run("success_conflict_markers", func(t *testing.T) {
+		merged, conflict := merge3(base, "a\nb user\nc\nd\n", "a\nb tmpl\nc\nd\n")
+		assert.True(t, conflict)
+		assert.Equal(t, "a\n<<<<<<< current\nb user\n=======\nb tmpl\n>>>>>>> template\nc\nd\n", merged)
+	})
+	t.Run("success_conflict_without_base", func(t *testing.T) {
+		merged, conflict := merge3(entity.Empty, "user", "tmpl")
+		assert.True(t, conflict)
+		assert.Equal(t, "<<<<<<< current\nuser\n=======\ntmpl\n>>>>>>> template\n", merged)
+	})
+}
+
+func Test_lcsMatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success_match_longest_common_subsequence", func(t *testing.T) {
+		var (
+			a = []string{"a", "b", "c", "d", "e", "f", "b"}
+			b = []string{"x", "b", "d", "a", "e", "b", "y"}
+		)
+		assert.Equal(t, map[int]int{1: 1, 3: 2, 4: 4, 6: 5}, lcsMatch(a, b))
+	})
+	t.Run("success_match_without_common_lines", func(t *testing.T) {
+		assert.Empty(t, lcsMatch([]string{"a", "b"}, []string{"c"}))
+		assert.Empty(t, lcsMatch(nil, []string{"c"}))
+	})
+	t.Run("success_match_large_texts", func(t *testing.T) {
+		const size = 3000
+		var a, b []string
+		for i := 0; i < size; i++ {
+			a = append(a, strconv.Itoa(i))
+			if i%10 != 0 {
+				b = append(b, strconv.Itoa(i))
+			}
+		}
+		match := lcsMatch(a, b)
+		assert.Len(t, match, len(b))
+		for i, j := range match {
+			assert.Equal(t, a[i], b[j])
+		}
+	})
+}
+
+func Test_Updater(t *testing.T) {
+	SkipSLowTest(t)
+
+	const (
+		someFile = "file_name.txt"
+	)
+
+	var (
+		mockLogger = MockLogger{
+			infof: func(format string, args ...any) {},
+		}
+	)
+
+	newFile := func(path, data string) entity.DataFile {
+		return entity.DataFile{FileInfo: entity.NewFileInfo(path), Data: []byte(data)}
+	}
+
+	t.Run("success_merge_with_base", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a        = assert.New(t)
+				path     = filepath.Join(tmpDir, someFile)
+				basePath = filepath.Join(tmpDir, manifestBaseDir, someFile)
+				updater  = &Updater{
+					logger: mockLogger,
+					basePathFn: func(p string) (string, bool) {
+						a.Equal(path, p)
+						return basePath, true
+					},
+				}
+			)
+			a.NoError(os.MkdirAll(filepath.Dir(basePath), os.ModePerm))
+			a.NoError(os.WriteFile(basePath, []byte("a\nb\nc\n"), os.ModePerm))
+			a.NoError(os.WriteFile(path, []byte("a user\nb\nc\n"), os.ModePerm))
+
+			file, err := NewUpdateFileStrategy(updater).Apply(newFile(path, "a\nb\nc tmpl\n"))
+			a.NoError(err)
+			a.Equal("a user\nb\nc tmpl\n", string(file.Data))
+			a.Empty(updater.Conflicts())
+		})
+	})
+	t.Run("success_reject_conflict", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a    = assert.New(t)
+				path = filepath.Join(tmpDir, someFile)
+			)
+			a.NoError(os.WriteFile(path, []byte("user\n"), os.ModePerm))
+
+			journal := NewJournal(mockLogger)
+			updater := NewUpdater(filepath.Join(tmpDir, ManifestPath), true, journal, mockLogger)
+			file, err := updater.Merge(newFile(path, "tmpl\n"))
+			a.NoError(err)
+			a.Equal("user\n", string(file.Data))
+			a.Equal([]string{path}, updater.Conflicts())
+			AssertFileDataEqual(t, path+rejectFileExt, []byte("tmpl\n"))
+			info, err := os.Stat(path + rejectFileExt)
+			a.NoError(err)
+			a.Zero(info.Mode().Perm() &^ 0o644)
+
+			// the reject file is removed by the rollback
+			a.NoError(journal.Rollback())
+			a.NoFileExists(path + rejectFileExt)
+			AssertFileDataEqual(t, path, []byte("user\n"))
+		})
+	})
+	t.Run("success_keep_raw_conflict", func(t *testing.T) {
+		WithTempDir(t, func(tmpDir string) {
+			var (
+				a    = assert.New(t)
+				path = filepath.Join(tmpDir, someFile)
+				raw  = newFile(path, "tmpl\x00")
+			)
+			a.NoError(os.WriteFile(path, []byte("user\x00"), os.ModePerm))
+			raw.Raw = true
+
+			updater := NewUpdater(filepath.Join(tmpDir, ManifestPath), false, nil, mockLogger)
+			file, err := updater.Merge(raw)
+			a.NoError(err)
+			a.Equal("user\x00", string(file.Data))
+			a.Equal([]string{path}, updater.Conflicts())
+		})
+	})
+	t.Run("success_nil_updater", func(t *testing.T) {
+		var updater *Updater
+		file, err := updater.Merge(newFile(someFile, "tmpl"))
+		assert.NoError(t, err)
+		assert.Equal(t, "tmpl", string(file.Data))
+		assert.Empty(t, updater.Conflicts())
+	})
+}
@@ -0,0 +1,158 @@
+package exec
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+// ParallelNode is the action's executor of the [ParallelChain].
+type ParallelNode struct {
+	Executor entity.Executor
+	// Wait contains indexes of the nodes, which must be finished before the node.
+	Wait []int
+	// Logger is the buffer of the action's output, which is flushed after the execution (can be nil).
+	Logger *BufferedLogger
+}
+
+// ParallelChain executes the nodes concurrently (no more than `jobs` at the same time) in order of the nodes' `Wait`.
+// The first failure cancels the nodes that are not finished.
+type ParallelChain struct {
+	nodes  []ParallelNode
+	jobs   int
+	logger entity.Logger
+}
+
+func NewParallelChain(nodes []ParallelNode, jobs int, logger entity.Logger) *ParallelChain {
+	if jobs < 1 {
+		jobs = 1
+	}
+	return &ParallelChain{
+		nodes:  nodes,
+		jobs:   jobs,
+		logger: logger,
+	}
+}
+
+func (c *ParallelChain) Exec() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		flushMx  sync.Mutex
+		errOnce  sync.Once
+		firstErr error
+
+		jobs = make(chan struct{}, c.jobs)
+		done = make([]chan struct{}, len(c.nodes))
+	)
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	for i, node := range c.nodes {
+		wg.Add(1)
+		go func(i int, node ParallelNode) {
+			defer wg.Done()
+			defer close(done[i])
+
+			for _, w := range node.Wait {
+				select {
+				case <-done[w]:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case jobs <- struct{}{}:
+				defer func() { <-jobs }()
+			case <-ctx.Done():
+				return
+			}
+			if ctx.Err() != nil || node.Executor == nil {
+				return
+			}
+
+			err := execContext(ctx, node.Executor)
+
+			flushMx.Lock()
+			node.Logger.Flush(c.logger)
+			flushMx.Unlock()
+
+			if err != nil && ctx.Err() == nil {
+				errOnce.Do(func() {
+					firstErr = xerrors.Errorf("execute proc [%d]: %w", i, err)
+					cancel()
+				})
+			}
+		}(i, node)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// execContext executes the executor with the context when the executor supports cancellation.
+func execContext(ctx context.Context, executor entity.Executor) error {
+	if e, ok := executor.(interface {
+		ExecContext(ctx context.Context) error
+	}); ok {
+		return e.ExecContext(ctx)
+	}
+	return executor.Exec()
+}
+
+// BufferedLogger collects the messages to write them to the logger at once.
+type BufferedLogger struct {
+	mx      sync.Mutex
+	records []func(logger entity.Logger)
+}
+
+func NewBufferedLogger() *BufferedLogger {
+	return &BufferedLogger{}
+}
+
+func (l *BufferedLogger) Infof(format string, args ...any) {
+	l.add(func(logger entity.Logger) { logger.Infof(format, args...) })
+}
+
+func (l *BufferedLogger) Errorf(format string, args ...any) {
+	l.add(func(logger entity.Logger) { logger.Errorf(format, args...) })
+}
+
+func (l *BufferedLogger) Warnf(format string, args ...any) {
+	l.add(func(logger entity.Logger) { logger.Warnf(format, args...) })
+}
+
+func (l *BufferedLogger) Debugf(format string, args ...any) {
+	l.add(func(logger entity.Logger) { logger.Debugf(format, args...) })
+}
+
+func (l *BufferedLogger) Fatalf(format string, args ...any) {
+	l.add(func(logger entity.Logger) { logger.Fatalf(format, args...) })
+}
+
+func (l *BufferedLogger) add(record func(logger entity.Logger)) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.records = append(l.records, record)
+}
+
+// Flush writes the collected messages to the logger and resets the buffer.
+func (l *BufferedLogger) Flush(logger entity.Logger) {
+	if l == nil {
+		return
+	}
+
+	l.mx.Lock()
+	records := l.records
+	l.records = nil
+	l.mx.Unlock()
+
+	for _, record := range records {
+		record(logger)
+	}
+}
@@ -0,0 +1,123 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockParallelExecutor struct {
+	exec func(ctx context.Context) error
+}
+
+func (m mockParallelExecutor) Exec() error {
+	return m.exec(context.Background())
+}
+
+func (m mockParallelExecutor) ExecContext(ctx context.Context) error {
+	return m.exec(ctx)
+}
+
+func Test_ParallelChain(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success_execute_with_jobs_limit_and_wait", func(t *testing.T) {
+		var (
+			mx       sync.Mutex
+			order    []string
+			running  atomic.Int32
+			maxCount atomic.Int32
+		)
+		newExecutor := func(name string) mockParallelExecutor {
+			return mockParallelExecutor{exec: func(_ context.Context) error {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for {
+					current := maxCount.Load()
+					if n <= current || maxCount.CompareAndSwap(current, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+
+				mx.Lock()
+				defer mx.Unlock()
+				order = append(order, name)
+				return nil
+			}}
+		}
+
+		nodes := []ParallelNode{
+			{Executor: newExecutor("a")},
+			{Executor: newExecutor("b")},
+			{Executor: newExecutor("c")},
+			{Executor: newExecutor("d"), Wait: []int{0, 1, 2}},
+		}
+		err := NewParallelChain(nodes, 2, MockLogger{}).Exec()
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), maxCount.Load())
+		assert.Len(t, order, 4)
+		assert.Equal(t, "d", order[3])
+	})
+	t.Run("success_flush_buffered_output", func(t *testing.T) {
+		var (
+			logger   = NewBufferedLogger()
+			messages []string
+			mock     = MockLogger{
+				infof: func(format string, args ...any) {
+					messages = append(messages, fmt.Sprintf(format, args...))
+				},
+			}
+		)
+		nodes := []ParallelNode{
+			{
+				Executor: mockParallelExecutor{exec: func(_ context.Context) error {
+					logger.Infof("first [%d]", 1)
+					logger.Infof("second [%d]", 2)
+					return nil
+				}},
+				Logger: logger,
+			},
+		}
+		err := NewParallelChain(nodes, 2, mock).Exec()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"first [1]", "second [2]"}, messages)
+	})
+	t.Run("error_cancel_on_first_failure", func(t *testing.T) {
+		var (
+			canceled atomic.Bool
+			executed atomic.Bool
+			started  = make(chan struct{})
+		)
+		nodes := []ParallelNode{
+			{Executor: mockParallelExecutor{exec: func(_ context.Context) error {
+				<-started
+				return fmt.Errorf("some error")
+			}}},
+			{Executor: mockParallelExecutor{exec: func(ctx context.Context) error {
+				close(started)
+				select {
+				case <-ctx.Done():
+					canceled.Store(true)
+					return ctx.Err()
+				case <-time.After(5 * time.Second):
+					return nil
+				}
+			}}},
+			{Executor: mockParallelExecutor{exec: func(_ context.Context) error {
+				executed.Store(true)
+				return nil
+			}}, Wait: []int{0}},
+		}
+		err := NewParallelChain(nodes, 2, MockLogger{}).Exec()
+		assert.Error(t, err)
+		assert.Equal(t, "execute proc [0]: some error", err.Error())
+		assert.True(t, canceled.Load())
+		assert.False(t, executed.Load())
+	})
+}
@@ -35,12 +35,14 @@ func (p *RmAllExecutor) Exec() error {
 }
 
 type RmAllStrategy struct {
-	logger entity.Logger
+	journal *Journal
+	logger  entity.Logger
 }
 
-func NewRmAllStrategy(logger entity.Logger) *RmAllStrategy {
+func NewRmAllStrategy(journal *Journal, logger entity.Logger) *RmAllStrategy {
 	return &RmAllStrategy{
-		logger: logger,
+		journal: journal,
+		logger:  logger,
 	}
 }
 
@@ -52,6 +54,10 @@ func (p *RmAllStrategy) Apply(path string) error {
 			return xerrors.Errorf("rm [%s]: get names of the all files: %w", path, err)
 		}
 		for _, item := range contents {
+			err = p.journal.Remove(item)
+			if err != nil {
+				return xerrors.Errorf("rm content [%s]: %w", item, err)
+			}
 			err = os.RemoveAll(item)
 			if err != nil {
 				return xerrors.Errorf("rm content [%s]: %w", item, err)
@@ -61,7 +67,11 @@ func (p *RmAllStrategy) Apply(path string) error {
 		return nil
 	}
 
-	err := os.RemoveAll(path)
+	err := p.journal.Remove(path)
+	if err != nil {
+		return xerrors.Errorf("rm [%s]: %w", path, err)
+	}
+	err = os.RemoveAll(path)
 	if err != nil {
 		return xerrors.Errorf("rm [%s]: %w", path, err)
 	}
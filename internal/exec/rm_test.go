@@ -37,7 +37,7 @@ func Test_RmAllStrategy(t *testing.T) {
 			a.NoError(err)
 			a.DirExists(path)
 
-			err = NewRmAllStrategy(mockLogger).Apply(path)
+			err = NewRmAllStrategy(nil, mockLogger).Apply(path)
 			a.NoError(err)
 			a.NoDirExists(path)
 		})
@@ -66,7 +66,7 @@ func Test_RmAllStrategy(t *testing.T) {
 			a.FileExists(filePath)
 			a.Equal(filePath, file.Name())
 
-			err = NewRmAllStrategy(mockLogger).Apply(filePath)
+			err = NewRmAllStrategy(nil, mockLogger).Apply(filePath)
 			a.NoError(err)
 			a.NoFileExists(filePath)
 			a.DirExists(dir)
@@ -105,7 +105,7 @@ func Test_RmAllStrategy(t *testing.T) {
 				a.FileExists(filePath)
 			}
 
-			err = NewRmAllStrategy(mockLogger).Apply(rmPath)
+			err = NewRmAllStrategy(nil, mockLogger).Apply(rmPath)
 			a.NoError(err)
 			a.DirExists(dir)
 			for _, path := range filesPath {
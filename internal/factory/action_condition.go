@@ -0,0 +1,95 @@
+package factory
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+// ActionCondition evaluates `when` template expressions of the actions against the template data.
+type ActionCondition struct {
+	templateData    map[string]any
+	templateFns     map[string]any
+	templateOptions []string
+
+	logger entity.Logger
+}
+
+func NewActionCondition(
+	templateData map[string]any,
+	templateOptions []string,
+	logger entity.Logger,
+) *ActionCondition {
+	return &ActionCondition{
+		templateData:    templateData,
+		templateFns:     entity.TemplateFnsMap,
+		templateOptions: templateOptions,
+		logger:          logger,
+	}
+}
+
+// Match reports whether the action's `when` expression is true (empty expression is always true).
+func (c *ActionCondition) Match(action, when string) (bool, error) {
+	match, err := c.eval(action, when)
+	if err != nil || match {
+		return match, err
+	}
+	c.logger.Infof("action will be skipped: [%s]: `when` is false [%s]", action, strings.TrimSpace(when))
+	return false, nil
+}
+
+// MatchEntry reports whether the `when` expression of the action's entry is true (empty expression is always true).
+func (c *ActionCondition) MatchEntry(entry, when string) (bool, error) {
+	match, err := c.eval(entry, when)
+	if err != nil {
+		return false, xerrors.Errorf("entry [%s]: %w", entry, err)
+	}
+	if !match {
+		c.logger.Infof("entry will be skipped: [%s]: `when` is false [%s]", entry, strings.TrimSpace(when))
+	}
+	return match, nil
+}
+
+func (c *ActionCondition) eval(name, when string) (bool, error) {
+	if c == nil || when == entity.Empty {
+		return true, nil
+	}
+
+	expr := strings.TrimSpace(when)
+	if expr == entity.Empty {
+		return false, nil
+	}
+
+	res, err := entity.NewTemplateProc(c.templateData, c.templateFns, c.templateOptions).
+		Process(name, "{{ if "+expr+" }}"+strconv.FormatBool(true)+"{{ end }}")
+	if err != nil {
+		return false, xerrors.Errorf("evaluate `when` [%s]: %w", expr, err)
+	}
+	return res == strconv.FormatBool(true), nil
+}
+
+// FilterEntries returns the entries of the action which `when` expressions are true.
+func FilterEntries[T any](
+	condition *ActionCondition,
+	entries []T,
+	entryFn func(entry T) (name, when string),
+) ([]T, error) {
+	if condition == nil {
+		return entries, nil
+	}
+	filtered := make([]T, 0, len(entries))
+	for _, entry := range entries {
+		name, when := entryFn(entry)
+		match, err := condition.MatchEntry(name, when)
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,139 @@
+package factory
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+func Test_ActionCondition_Match(t *testing.T) {
+	t.Parallel()
+
+	const action = "files_db"
+
+	var (
+		templateData    = map[string]any{"vars": map[string]any{"db": true, "cache": false}}
+		templateOptions = []string{fmt.Sprintf("%v=%v", entity.TemplateOptionsMissingKey, entity.MissingKeyError)}
+	)
+
+	t.Run("success_match_true", func(t *testing.T) {
+		logger := &MockLogger{}
+		condition := NewActionCondition(templateData, templateOptions, logger)
+		for _, when := range []string{entity.Empty, ".vars.db", "and .vars.db (not .vars.cache)"} {
+			match, err := condition.Match(action, when)
+			assert.NoError(t, err)
+			assert.True(t, match, when)
+		}
+		assert.Empty(t, logger.Infos())
+	})
+	t.Run("success_match_false", func(t *testing.T) {
+		logger := &MockLogger{}
+		condition := NewActionCondition(templateData, templateOptions, logger)
+		for _, when := range []string{".vars.cache", " not .vars.db ", "eq .vars.db false", " "} {
+			match, err := condition.Match(action, when)
+			assert.NoError(t, err)
+			assert.False(t, match, when)
+		}
+		assert.Contains(t, logger.Infos(), "action will be skipped: [files_db]: `when` is false [.vars.cache]")
+	})
+	t.Run("success_nil_condition", func(t *testing.T) {
+		var condition *ActionCondition
+		match, err := condition.Match(action, ".vars.cache")
+		assert.NoError(t, err)
+		assert.True(t, match)
+	})
+	t.Run("error_when_missing_key", func(t *testing.T) {
+		condition := NewActionCondition(templateData, templateOptions, &MockLogger{})
+		match, err := condition.Match(action, ".vars.unknown.key")
+		assert.Error(t, err)
+		assert.False(t, match)
+	})
+}
+
+func Test_ExecutorBuilderFactory_when(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger    = &MockLogger{}
+		condition = NewActionCondition(map[string]any{"db": false}, nil, logger)
+		created   []string
+	)
+	consumer := func(action string, vals []string, _ entity.Logger, _ bool) (entity.Executor, error) {
+		created = append(created, action)
+		return nil, nil
+	}
+	factory := NewExecutorBuilderFactory(
+		[]entity.Action[[]string]{
+			{Name: "dirs", Priority: 1, Val: []string{"a"}},
+			{Name: "dirs_db", Priority: 2, When: ".db", Val: []string{"db"}},
+			{Name: "dirs_err", Priority: 3, When: ".db.x", Val: []string{"err"}},
+		},
+		consumer,
+		DummyActionFilter{},
+		condition,
+	)
+
+	builders := factory.Create(false)
+	assert.Len(t, builders, 3)
+	assert.False(t, builders[0].Skipped)
+	assert.True(t, builders[1].Skipped)
+	assert.Nil(t, builders[1].ProcFn)
+	assert.False(t, builders[2].Skipped)
+
+	_, err := builders[0].ProcFn(logger)
+	assert.NoError(t, err)
+	_, err = builders[2].ProcFn(logger)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"dirs"}, created)
+}
+
+func Test_FilterEntries(t *testing.T) {
+	t.Parallel()
+
+	type entry struct {
+		name string
+		when string
+	}
+	entryFn := func(e entry) (string, string) {
+		return e.name, e.when
+	}
+
+	var (
+		templateData = map[string]any{"db": true, "cache": false}
+		entries      = []entry{{name: "a"}, {name: "db", when: ".db"}, {name: "cache", when: ".cache"}}
+	)
+
+	t.Run("success_filter_entries", func(t *testing.T) {
+		logger := &MockLogger{}
+		filtered, err := FilterEntries(NewActionCondition(templateData, nil, logger), entries, entryFn)
+		assert.NoError(t, err)
+		assert.Equal(t, []entry{{name: "a"}, {name: "db", when: ".db"}}, filtered)
+		assert.Equal(t, []string{"entry will be skipped: [cache]: `when` is false [.cache]"}, logger.Infos())
+	})
+	t.Run("success_nil_condition", func(t *testing.T) {
+		filtered, err := FilterEntries(nil, entries, entryFn)
+		assert.NoError(t, err)
+		assert.Equal(t, entries, filtered)
+	})
+	t.Run("error_entry_when", func(t *testing.T) {
+		_, err := FilterEntries(NewActionCondition(templateData, nil, &MockLogger{}), []entry{{name: "x", when: ".db.x"}}, entryFn)
+		assert.ErrorContains(t, err, "entry [x]: evaluate `when` [.db.x]")
+	})
+	t.Run("success_skip_command_entry", func(t *testing.T) {
+		logger := &MockLogger{}
+		executor, err := NewRunCommandExecutorFactory(NewActionCondition(templateData, nil, logger)).Create(
+			"cmd",
+			[]entity.Command{{Cmd: "echo", Args: []string{"db"}, When: ".db"}, {Cmd: "echo", Args: []string{"cache"}, When: ".cache"}},
+			logger,
+			true,
+		)
+		assert.NoError(t, err)
+		assert.NoError(t, executor.Exec())
+		assert.Contains(t, logger.Infos(), "entry will be skipped: [echo cache]: `when` is false [.cache]")
+		assert.Contains(t, logger.Infos(), "execute [dir: .]: echo db")
+		assert.NotContains(t, logger.Infos(), "execute [dir: .]: echo cache")
+	})
+}
@@ -2,23 +2,26 @@ package factory
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
 	"golang.org/x/xerrors"
 
 	"github.com/kozmod/progen/internal/entity"
+	"github.com/kozmod/progen/internal/exec"
 )
 
 type (
 	executorBuilderFactory interface {
-		Create(logger entity.Logger, dryRun bool) []entity.ExecutorBuilder
+		Create(dryRun bool) []entity.ExecutorBuilder
 	}
 )
 
 type ExecutorChainFactory struct {
 	logger entity.Logger
 	dryRun bool
+	jobs   int
 
 	executorBuilderFactories []executorBuilderFactory
 	createFn                 func([]entity.Executor) entity.Executor
@@ -27,6 +30,7 @@ type ExecutorChainFactory struct {
 func NewExecutorChainFactory(
 	logger entity.Logger,
 	dryRun bool,
+	jobs int,
 	createFn func([]entity.Executor) entity.Executor,
 	executorBuilderFactories ...executorBuilderFactory,
 
@@ -35,6 +39,7 @@ func NewExecutorChainFactory(
 		createFn:                 createFn,
 		logger:                   logger,
 		dryRun:                   dryRun,
+		jobs:                     jobs,
 		executorBuilderFactories: executorBuilderFactories,
 	}
 }
@@ -44,13 +49,14 @@ func (f ExecutorChainFactory) Create() (entity.Executor, error) {
 		allBuilders []entity.ExecutorBuilder
 	)
 	for _, factory := range f.executorBuilderFactories {
-		builder := factory.Create(f.logger, f.dryRun)
+		builder := factory.Create(f.dryRun)
 		allBuilders = append(allBuilders, builder...)
 	}
 
-	sort.Slice(allBuilders, func(i, j int) bool {
-		return allBuilders[i].Priority < allBuilders[j].Priority
-	})
+	allBuilders, err := orderBuilders(allBuilders)
+	if err != nil {
+		return nil, xerrors.Errorf("order actions: %w", err)
+	}
 
 	actionNames := make([]string, len(allBuilders))
 	for i, builder := range allBuilders {
@@ -58,9 +64,18 @@ func (f ExecutorChainFactory) Create() (entity.Executor, error) {
 	}
 	f.logger.Infof("action is going to be execute ('priopiry':'name')[%s]", strings.Join(actionNames, ","))
 
+	if f.jobs > 1 {
+		nodes, err := parallelNodes(allBuilders)
+		if err != nil {
+			return nil, err
+		}
+		f.logger.Infof("actions are going to be execute concurrently: jobs [%d]", f.jobs)
+		return f.createFn([]entity.Executor{exec.NewParallelChain(nodes, f.jobs, f.logger)}), nil
+	}
+
 	executors := make([]entity.Executor, 0, len(allBuilders))
 	for _, builder := range allBuilders {
-		e, err := builder.ProcFn()
+		e, err := builder.ProcFn(f.logger)
 		if err != nil {
 			return nil, xerrors.Errorf("configure executor [%s]: %w", builder.Action, err)
 		}
@@ -73,28 +88,172 @@ func (f ExecutorChainFactory) Create() (entity.Executor, error) {
 	return f.createFn(executors), nil
 }
 
+// parallelNodes creates the nodes of the ordered builders with the buffered output.
+// The parallel action and the action with dependencies (`DependsOn`) wait for
+// their dependencies and the previous sequential action, so such actions without
+// the dependency path between them are executed concurrently.
+// The sequential action (neither parallel nor with dependencies) waits for all previous actions.
+func parallelNodes(builders []entity.ExecutorBuilder) ([]exec.ParallelNode, error) {
+	var (
+		nodes   = make([]exec.ParallelNode, len(builders))
+		indexes = make(map[string][]int)
+		barrier = -1
+	)
+	for i, builder := range builders {
+		logger := exec.NewBufferedLogger()
+		e, err := builder.ProcFn(logger)
+		if err != nil {
+			return nil, xerrors.Errorf("configure executor [%s]: %w", builder.Action, err)
+		}
+
+		// the builders are ordered topologically, so the dependencies are indexed before the builder
+		var wait []int
+		switch {
+		case !builder.Parallel && len(builder.DependsOn) == 0:
+			for j := max(barrier, 0); j < i; j++ {
+				wait = append(wait, j)
+			}
+			barrier = i
+		default:
+			if barrier >= 0 {
+				wait = append(wait, barrier)
+			}
+			for _, dependency := range builder.DependsOn {
+				wait = append(wait, indexes[dependency]...)
+			}
+		}
+
+		nodes[i] = exec.ParallelNode{
+			Executor: e,
+			Wait:     wait,
+			Logger:   logger,
+		}
+		indexes[builder.Action] = append(indexes[builder.Action], i)
+	}
+	return nodes, nil
+}
+
+// orderBuilders removes skipped builders and orders the others topologically by `DependsOn`,
+// the priority (line of the action) is used as the tiebreak.
+func orderBuilders(builders []entity.ExecutorBuilder) ([]entity.ExecutorBuilder, error) {
+	sort.SliceStable(builders, func(i, j int) bool {
+		return builders[i].Priority < builders[j].Priority
+	})
+
+	var (
+		active  = make([]entity.ExecutorBuilder, 0, len(builders))
+		skipped = make(map[string]struct{})
+		indexes = make(map[string][]int)
+	)
+	for _, builder := range builders {
+		if builder.Skipped {
+			skipped[builder.Action] = struct{}{}
+			continue
+		}
+		indexes[builder.Action] = append(indexes[builder.Action], len(active))
+		active = append(active, builder)
+	}
+
+	var (
+		inDegree   = make([]int, len(active))
+		dependents = make([][]int, len(active))
+	)
+	for i, builder := range active {
+		for _, dependency := range builder.DependsOn {
+			dependencyIndexes, ok := indexes[dependency]
+			if !ok {
+				if _, ok = skipped[dependency]; ok {
+					return nil, xerrors.Errorf("action [%s] depends on skipped action [%s]", builder.Action, dependency)
+				}
+				return nil, xerrors.Errorf("action [%s] depends on unknown action [%s]", builder.Action, dependency)
+			}
+			for _, j := range dependencyIndexes {
+				dependents[j] = append(dependents[j], i)
+				inDegree[i]++
+			}
+		}
+	}
+
+	var (
+		ordered = make([]entity.ExecutorBuilder, 0, len(active))
+		done    = make([]bool, len(active))
+	)
+	for len(ordered) < len(active) {
+		// the first ready builder in order of the priority
+		next := -1
+		for i := range active {
+			if !done[i] && inDegree[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			return nil, xerrors.Errorf("dependency cycle: [%s]", strings.Join(dependencyCycle(active, done, indexes), " -> "))
+		}
+		done[next] = true
+		ordered = append(ordered, active[next])
+		for _, dependent := range dependents[next] {
+			inDegree[dependent]--
+		}
+	}
+	return ordered, nil
+}
+
+// dependencyCycle returns the names of the actions of the cycle from the not ordered builders.
+func dependencyCycle(builders []entity.ExecutorBuilder, done []bool, indexes map[string][]int) []string {
+	var (
+		visited = make(map[int]int) // index of the builder -> position in the path
+		path    []int
+		current = slices.Index(done, false)
+	)
+	for {
+		if pos, ok := visited[current]; ok {
+			cycle := make([]string, 0, len(path)-pos+1)
+			for _, i := range path[pos:] {
+				cycle = append(cycle, builders[i].Action)
+			}
+			return append(cycle, builders[current].Action)
+		}
+		visited[current] = len(path)
+		path = append(path, current)
+
+		// not ordered builder always has not ordered dependency
+		for _, dependency := range builders[current].DependsOn {
+			if i := slices.IndexFunc(indexes[dependency], func(i int) bool { return !done[i] }); i >= 0 {
+				current = indexes[dependency][i]
+				break
+			}
+		}
+	}
+}
+
 type (
-	actionValConsumer[T any] func(vals []T, logger entity.Logger, dryRun bool) (entity.Executor, error)
+	// actionValConsumer creates the executor of the action's values.
+	actionValConsumer[T any] func(action string, vals []T, logger entity.Logger, dryRun bool) (entity.Executor, error)
 )
 
 type ExecutorBuilderFactory[T any] struct {
 	actionsSupplier   []entity.Action[[]T]
 	actionValConsumer actionValConsumer[T]
 	actionFilter      entity.ActionFilter
+	actionCondition   *ActionCondition
 }
 
 func NewExecutorBuilderFactory[T any](
 	actionSupplier []entity.Action[[]T],
 	actionValConsumer actionValConsumer[T],
 	actionFilter entity.ActionFilter,
+	actionCondition *ActionCondition,
 ) *ExecutorBuilderFactory[T] {
 	return &ExecutorBuilderFactory[T]{
 		actionsSupplier:   actionSupplier,
 		actionValConsumer: actionValConsumer,
-		actionFilter:      actionFilter}
+		actionFilter:      actionFilter,
+		actionCondition:   actionCondition,
+	}
 }
 
-func (y ExecutorBuilderFactory[T]) Create(logger entity.Logger, dryRun bool) []entity.ExecutorBuilder {
+func (y ExecutorBuilderFactory[T]) Create(dryRun bool) []entity.ExecutorBuilder {
 	var (
 		actions  = y.actionsSupplier
 		builders = make([]entity.ExecutorBuilder, 0, len(actions))
@@ -105,17 +264,62 @@ func (y ExecutorBuilderFactory[T]) Create(logger entity.Logger, dryRun bool) []e
 			name = a.Name
 		)
 		if !y.actionFilter.MatchString(name) {
+			builders = append(builders, entity.ExecutorBuilder{Action: name, Priority: a.Priority, Skipped: true})
+			continue
+		}
+		match, err := y.actionCondition.Match(name, a.When)
+		if err != nil {
+			builders = append(builders,
+				entity.ExecutorBuilder{
+					Action:    name,
+					Priority:  a.Priority,
+					DependsOn: a.DependsOn,
+					Parallel:  a.Parallel,
+					ProcFn: func(entity.Logger) (entity.Executor, error) {
+						return nil, err
+					},
+				})
+			continue
+		}
+		if !match {
+			builders = append(builders, entity.ExecutorBuilder{Action: name, Priority: a.Priority, Skipped: true})
 			continue
 		}
 		builders = append(builders,
 			entity.ExecutorBuilder{
-				Action:   name,
-				Priority: a.Priority,
-				ProcFn: func() (entity.Executor, error) {
-					executor, err := y.actionValConsumer(a.Val, logger, dryRun)
-					return executor, err
+				Action:    name,
+				Priority:  a.Priority,
+				DependsOn: a.DependsOn,
+				Parallel:  a.Parallel,
+				ProcFn: func(logger entity.Logger) (entity.Executor, error) {
+					executor, err := y.actionValConsumer(name, a.Val, logger, dryRun)
+					if err != nil {
+						return nil, err
+					}
+					return y.actionExecutor(a, executor, logger, dryRun)
 				},
 			})
 	}
 	return builders
 }
+
+// actionExecutor wraps the action's executor to execute it with the action's hooks.
+func (y ExecutorBuilderFactory[T]) actionExecutor(
+	action entity.Action[[]T],
+	executor entity.Executor,
+	logger entity.Logger,
+	dryRun bool,
+) (entity.Executor, error) {
+	before, err := newHookExecutor(action.Before, y.actionCondition, logger, dryRun)
+	if err != nil {
+		return nil, xerrors.Errorf("before hook: %w", err)
+	}
+	after, err := newHookExecutor(action.After, y.actionCondition, logger, dryRun)
+	if err != nil {
+		return nil, xerrors.Errorf("after hook: %w", err)
+	}
+	if executor == nil && before == nil && after == nil {
+		return nil, nil
+	}
+	return exec.NewActionExecutor(action.Name, executor, before, after), nil
+}
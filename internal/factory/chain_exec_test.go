@@ -0,0 +1,171 @@
+package factory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kozmod/progen/internal/entity"
+	"github.com/kozmod/progen/internal/exec"
+)
+
+func Test_orderBuilders(t *testing.T) {
+	t.Parallel()
+
+	builder := func(action string, priority int, dependsOn ...string) entity.ExecutorBuilder {
+		return entity.ExecutorBuilder{Action: action, Priority: priority, DependsOn: dependsOn}
+	}
+	skipped := func(action string, priority int) entity.ExecutorBuilder {
+		return entity.ExecutorBuilder{Action: action, Priority: priority, Skipped: true}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		builders []entity.ExecutorBuilder
+		expected []string
+		err      string
+	}{
+		{
+			name:     "success_order_by_priority",
+			builders: []entity.ExecutorBuilder{builder("c", 3), builder("a", 1), builder("b", 2)},
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name: "success_order_topologically",
+			builders: []entity.ExecutorBuilder{
+				builder("files", 1, "dirs"),
+				builder("cmd", 2, "files", "dirs"),
+				builder("dirs", 3),
+			},
+			expected: []string{"dirs", "files", "cmd"},
+		},
+		{
+			name: "success_priority_tiebreak_of_ready_actions",
+			builders: []entity.ExecutorBuilder{
+				builder("cmd", 1, "dirs"),
+				builder("rm", 2),
+				builder("dirs", 3),
+				builder("files", 4),
+			},
+			expected: []string{"rm", "dirs", "cmd", "files"},
+		},
+		{
+			name: "success_depend_on_all_actions_with_the_same_name",
+			builders: []entity.ExecutorBuilder{
+				builder("cmd", 1, "dirs"),
+				builder("dirs", 2),
+				builder("dirs", 3),
+			},
+			expected: []string{"dirs", "dirs", "cmd"},
+		},
+		{
+			name:     "success_remove_skipped",
+			builders: []entity.ExecutorBuilder{builder("a", 1), skipped("b", 2), builder("c", 3)},
+			expected: []string{"a", "c"},
+		},
+		{
+			name:     "error_depends_on_unknown_action",
+			builders: []entity.ExecutorBuilder{builder("a", 1, "x")},
+			err:      "action [a] depends on unknown action [x]",
+		},
+		{
+			name:     "error_depends_on_skipped_action",
+			builders: []entity.ExecutorBuilder{builder("a", 1, "b"), skipped("b", 2)},
+			err:      "action [a] depends on skipped action [b]",
+		},
+		{
+			name:     "error_self_dependency",
+			builders: []entity.ExecutorBuilder{builder("a", 1, "a")},
+			err:      "dependency cycle: [a -> a]",
+		},
+		{
+			name: "error_dependency_cycle",
+			builders: []entity.ExecutorBuilder{
+				builder("dirs", 1),
+				builder("a", 2, "dirs", "c"),
+				builder("b", 3, "a"),
+				builder("c", 4, "b"),
+			},
+			err: "dependency cycle: [a -> c -> b -> a]",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ordered, err := orderBuilders(tc.builders)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+
+			actions := make([]string, len(ordered))
+			for i, b := range ordered {
+				actions[i] = b.Action
+			}
+			assert.Equal(t, tc.expected, actions)
+		})
+	}
+}
+
+func Test_parallelNodes(t *testing.T) {
+	t.Parallel()
+
+	builder := func(action string, dependsOn ...string) entity.ExecutorBuilder {
+		return entity.ExecutorBuilder{
+			Action:    action,
+			DependsOn: dependsOn,
+			ProcFn: func(logger entity.Logger) (entity.Executor, error) {
+				return nil, nil
+			},
+		}
+	}
+
+	parallel := func(action string, dependsOn ...string) entity.ExecutorBuilder {
+		b := builder(action, dependsOn...)
+		b.Parallel = true
+		return b
+	}
+	waits := func(nodes []exec.ParallelNode) [][]int {
+		w := make([][]int, len(nodes))
+		for i, node := range nodes {
+			w[i] = node.Wait
+			assert.NotNil(t, node.Logger)
+		}
+		return w
+	}
+
+	t.Run("success_wait_only_for_dependencies", func(t *testing.T) {
+		nodes, err := parallelNodes([]entity.ExecutorBuilder{
+			parallel("dirs"),
+			parallel("cmd_go"),
+			parallel("dirs"),
+			builder("cmd_npm", "dirs"),
+			parallel("cmd_buf", "cmd_go"),
+			builder("cmd_done", "cmd_npm", "cmd_buf"),
+		})
+		assert.NoError(t, err)
+		// the actions without the dependency path don't wait for each other
+		assert.Equal(t, [][]int{nil, nil, nil, {0, 2}, {1}, {3, 4}}, waits(nodes))
+	})
+	t.Run("success_sequential_action_split", func(t *testing.T) {
+		nodes, err := parallelNodes([]entity.ExecutorBuilder{
+			builder("dirs"),
+			parallel("cmd_go"),
+			builder("cmd_npm", "cmd_go"),
+			builder("files"),
+			parallel("cmd_buf"),
+			builder("cmd_done"),
+		})
+		assert.NoError(t, err)
+		// the sequential action waits for all previous actions, the next actions wait for it
+		assert.Equal(t, [][]int{nil, {0}, {0, 1}, {0, 1, 2}, {3}, {3, 4}}, waits(nodes))
+	})
+	t.Run("error_configure_executor", func(t *testing.T) {
+		failed := builder("cmd")
+		failed.ProcFn = func(logger entity.Logger) (entity.Executor, error) {
+			return nil, assert.AnError
+		}
+		_, err := parallelNodes([]entity.ExecutorBuilder{builder("dirs"), failed})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "configure executor [cmd]")
+	})
+}
@@ -8,7 +8,7 @@ import (
 )
 
 //goland:noinspection SpellCheckingInspection
-func NewRunCommandExecutor(cmds []entity.Command, logger entity.Logger, dryRun bool) (entity.Executor, error) {
+func NewRunCommandExecutor(_ string, cmds []entity.Command, logger entity.Logger, dryRun bool) (entity.Executor, error) {
 	if len(cmds) == 0 {
 		logger.Infof("`cmd` section is empty")
 		return nil, nil
@@ -38,3 +38,24 @@ func NewRunCommandExecutor(cmds []entity.Command, logger entity.Logger, dryRun b
 		return exec.NewCommandExecutor(commands, logger), nil
 	}
 }
+
+// RunCommandExecutorFactory creates the commands executor of the commands which `when` expressions are true.
+type RunCommandExecutorFactory struct {
+	condition *ActionCondition
+}
+
+func NewRunCommandExecutorFactory(condition *ActionCondition) *RunCommandExecutorFactory {
+	return &RunCommandExecutorFactory{
+		condition: condition,
+	}
+}
+
+func (f *RunCommandExecutorFactory) Create(_ string, cmds []entity.Command, logger entity.Logger, dryRun bool) (entity.Executor, error) {
+	cmds, err := FilterEntries(f.condition, cmds, func(cmd entity.Command) (string, string) {
+		return strings.Join(append([]string{cmd.Cmd}, cmd.Args...), entity.Space), cmd.When
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewRunCommandExecutor(entity.Empty, cmds, logger, dryRun)
+}
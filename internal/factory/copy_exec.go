@@ -0,0 +1,45 @@
+package factory
+
+import (
+	"golang.org/x/xerrors"
+
+	"github.com/kozmod/progen/internal/entity"
+	"github.com/kozmod/progen/internal/exec"
+)
+
+// CopyExecFactory creates the executor, which saves the local dirs trees (which `when` expressions are true)
+// to the destination dirs as the `fs save` action.
+type CopyExecFactory struct {
+	condition *ActionCondition
+	fsSave    *FsSaveExecFactory
+}
+
+func NewCopyExecFactory(condition *ActionCondition, fsSave *FsSaveExecFactory) *CopyExecFactory {
+	return &CopyExecFactory{
+		condition: condition,
+		fsSave:    fsSave,
+	}
+}
+
+func (f *CopyExecFactory) Create(action string, copies []entity.Copy, logger entity.Logger, dryRun bool) (entity.Executor, error) {
+	copies, err := FilterEntries(f.condition, copies, func(cp entity.Copy) (string, string) {
+		return cp.Src, cp.When
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]entity.TargetFs, 0, len(copies))
+	for _, cp := range copies {
+		filter, err := exec.NewDirPathFilter(cp.Src, cp.Include, cp.Exclude)
+		if err != nil {
+			return nil, xerrors.Errorf("copy [%s]: %w", cp.Src, err)
+		}
+		targets = append(targets, entity.TargetFs{
+			TargetDir: cp.Dst,
+			Fs:        exec.NewLocalFS(cp.Src, filter),
+			Raw:       !cp.Template,
+		})
+	}
+	return f.fsSave.Create(action, targets, logger, dryRun)
+}
@@ -1,13 +1,22 @@
 package factory
 
 import (
+	"os"
 	"slices"
 
 	"github.com/kozmod/progen/internal/entity"
 	"github.com/kozmod/progen/internal/exec"
 )
 
-func NewMkdirExecutor(dirs []string, logger entity.Logger, dryRun bool) (entity.Executor, error) {
+func newMkdirExecutor(
+	dirs []string,
+	mode os.FileMode,
+	modes map[string]os.FileMode,
+	journal *exec.Journal,
+	recorder *exec.ManifestRecorder,
+	logger entity.Logger,
+	dryRun bool,
+) (entity.Executor, error) {
 	if len(dirs) == 0 {
 		logger.Infof("mkdir executor: `dir` section is empty")
 		return nil, nil
@@ -19,5 +28,51 @@ func NewMkdirExecutor(dirs []string, logger entity.Logger, dryRun bool) (entity.
 		return exec.NewDirExecutor(dirSet, []entity.DirStrategy{exec.NewDryRunMkdirAllStrategy(logger)}), nil
 	}
 
-	return exec.NewDirExecutor(dirSet, []entity.DirStrategy{exec.NewMkdirAllStrategy(logger)}), nil
+	return exec.NewDirExecutor(dirSet, []entity.DirStrategy{
+		exec.NewManifestDirStrategy(recorder),
+		exec.NewMkdirAllStrategy(mode, modes, journal, logger),
+	}), nil
+}
+
+// MkdirExecutorFactory creates the mkdir executor of the directories which `when` expressions are true.
+type MkdirExecutorFactory struct {
+	condition *ActionCondition
+	mode      os.FileMode
+	journal   *exec.Journal
+	manifest  *exec.Manifest
+}
+
+func NewMkdirExecutorFactory(
+	condition *ActionCondition,
+	mode os.FileMode,
+	journal *exec.Journal,
+	manifest *exec.Manifest,
+) *MkdirExecutorFactory {
+	return &MkdirExecutorFactory{
+		condition: condition,
+		mode:      mode,
+		journal:   journal,
+		manifest:  manifest,
+	}
+}
+
+func (f *MkdirExecutorFactory) Create(action string, dirs []entity.Dir, logger entity.Logger, dryRun bool) (entity.Executor, error) {
+	dirs, err := FilterEntries(f.condition, dirs, func(dir entity.Dir) (string, string) {
+		return dir.Path, dir.When
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		paths = make([]string, len(dirs))
+		modes = make(map[string]os.FileMode)
+	)
+	for i, dir := range dirs {
+		paths[i] = dir.Path
+		if dir.Mode != 0 {
+			modes[dir.Path] = dir.Mode
+		}
+	}
+	return newMkdirExecutor(paths, f.mode, modes, f.journal, f.manifest.Recorder(action), logger, dryRun)
 }
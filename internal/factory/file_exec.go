@@ -12,19 +12,37 @@ import (
 type FileExecutorFactory struct {
 	templateData    map[string]any
 	templateOptions []string
+	skipExt         []string
+	modes           entity.FileModes
+	policy          *exec.OnExistsPolicy
+	journal         *exec.Journal
+	manifest        *exec.Manifest
+	updater         *exec.Updater
 }
 
 func NewFileExecutorFactory(
 	templateData map[string]any,
 	templateOptions []string,
+	skipExt []string,
+	modes entity.FileModes,
+	policy *exec.OnExistsPolicy,
+	journal *exec.Journal,
+	manifest *exec.Manifest,
+	updater *exec.Updater,
 ) *FileExecutorFactory {
 	return &FileExecutorFactory{
 		templateData:    templateData,
 		templateOptions: templateOptions,
+		skipExt:         skipExt,
+		modes:           modes,
+		policy:          policy,
+		journal:         journal,
+		manifest:        manifest,
+		updater:         updater,
 	}
 }
 
-func (ff *FileExecutorFactory) Create(files []entity.UndefinedFile, logger entity.Logger, dryRun bool) (entity.Executor, error) {
+func (ff *FileExecutorFactory) Create(action string, files []entity.UndefinedFile, logger entity.Logger, dryRun bool) (entity.Executor, error) {
 	if len(files) == 0 {
 		logger.Infof("`files` section is empty")
 		return nil, nil
@@ -35,18 +53,27 @@ func (ff *FileExecutorFactory) Create(files []entity.UndefinedFile, logger entit
 		file := entity.DataFile{
 			FileInfo: entity.NewFileInfo(f.Path),
 			Data:     *f.Data,
+			Source:   entity.FileSourceData,
+			OnExists: f.OnExists,
+			Mode:     f.Mode,
+			Raw:      f.Raw,
 		}
 		producer := exec.NewDummyProducer(file)
 		producers = append(producers, producer)
 	}
 
-	strategies := []entity.FileStrategy{exec.NewTemplateFileStrategy(ff.templateData, entity.TemplateFnsMap, ff.templateOptions)}
+	strategies := []entity.FileStrategy{exec.NewTemplateFileStrategy(ff.templateData, entity.TemplateFnsMap, ff.templateOptions, ff.skipExt)}
 
 	switch {
 	case dryRun:
 		strategies = append(strategies, exec.NewDryRunFileStrategy(logger))
 	default:
-		strategies = append(strategies, exec.NewSaveFileStrategy(logger))
+		recorder := ff.manifest.Recorder(action)
+		strategies = append(strategies,
+			exec.NewManifestFileStrategy(recorder, entity.Empty),
+			exec.NewUpdateFileStrategy(ff.updater),
+			exec.NewSaveFileStrategy(ff.modes, ff.policy, ff.journal, recorder, logger),
+		)
 	}
 	executor := exec.NewFilesExecutor(producers, strategies)
 
@@ -56,6 +83,13 @@ func (ff *FileExecutorFactory) Create(files []entity.UndefinedFile, logger entit
 type PreprocessorsFileExecutorFactory struct {
 	templateData    map[string]any
 	templateOptions []string
+	skipExt         []string
+	condition       *ActionCondition
+	modes           entity.FileModes
+	policy          *exec.OnExistsPolicy
+	journal         *exec.Journal
+	manifest        *exec.Manifest
+	updater         *exec.Updater
 
 	preprocess         bool
 	preprocessors      *exec.Preprocessors
@@ -65,6 +99,13 @@ type PreprocessorsFileExecutorFactory struct {
 func NewPreprocessorsFileExecutorFactory(
 	templateData map[string]any,
 	templateOptions []string,
+	skipExt []string,
+	condition *ActionCondition,
+	modes entity.FileModes,
+	policy *exec.OnExistsPolicy,
+	journal *exec.Journal,
+	manifest *exec.Manifest,
+	updater *exec.Updater,
 	preprocess bool,
 	preprocessors *exec.Preprocessors,
 	httpClientSupplier func(logger entity.Logger) *resty.Client,
@@ -72,13 +113,28 @@ func NewPreprocessorsFileExecutorFactory(
 	return &PreprocessorsFileExecutorFactory{
 		templateData:       templateData,
 		templateOptions:    templateOptions,
+		skipExt:            skipExt,
+		condition:          condition,
+		modes:              modes,
+		policy:             policy,
+		journal:            journal,
+		manifest:           manifest,
+		updater:            updater,
 		preprocess:         preprocess,
 		preprocessors:      preprocessors,
 		httpClientSupplier: httpClientSupplier,
 	}
 }
 
-func (ff *PreprocessorsFileExecutorFactory) Create(files []entity.UndefinedFile, logger entity.Logger, dryRun bool) (entity.Executor, error) {
+func (ff *PreprocessorsFileExecutorFactory) Create(action string, files []entity.UndefinedFile, logger entity.Logger, dryRun bool) (entity.Executor, error) {
+	// skipped files are never loaded
+	files, err := FilterEntries(ff.condition, files, func(file entity.UndefinedFile) (string, string) {
+		return file.Path, file.When
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	if len(files) == 0 {
 		logger.Infof("`files` section is empty")
 		return nil, nil
@@ -98,6 +154,10 @@ func (ff *PreprocessorsFileExecutorFactory) Create(files []entity.UndefinedFile,
 			file := entity.DataFile{
 				FileInfo: tmpl,
 				Data:     *f.Data,
+				Source:   entity.FileSourceData,
+				OnExists: f.OnExists,
+				Mode:     f.Mode,
+				Raw:      f.Raw,
 			}
 			producer = exec.NewDummyProducer(file)
 		case f.Get != nil:
@@ -108,6 +168,9 @@ func (ff *PreprocessorsFileExecutorFactory) Create(files []entity.UndefinedFile,
 					Headers:     f.Get.Headers,
 					QueryParams: f.Get.QueryParams,
 				},
+				OnExists: f.OnExists,
+				Mode:     f.Mode,
+				Raw:      f.Raw,
 			}
 
 			if client == nil {
@@ -119,6 +182,9 @@ func (ff *PreprocessorsFileExecutorFactory) Create(files []entity.UndefinedFile,
 			file := entity.LocalFile{
 				FileInfo:  tmpl,
 				LocalPath: *f.Local,
+				OnExists:  f.OnExists,
+				Mode:      f.Mode,
+				Raw:       f.Raw,
 			}
 			producer = exec.NewLocalProducer(file)
 
@@ -142,13 +208,18 @@ func (ff *PreprocessorsFileExecutorFactory) Create(files []entity.UndefinedFile,
 		ff.preprocessors.Add(preloader)
 	}
 
-	strategies := []entity.FileStrategy{exec.NewTemplateFileStrategy(ff.templateData, entity.TemplateFnsMap, ff.templateOptions)}
+	strategies := []entity.FileStrategy{exec.NewTemplateFileStrategy(ff.templateData, entity.TemplateFnsMap, ff.templateOptions, ff.skipExt)}
 
 	switch {
 	case dryRun:
 		strategies = append(strategies, exec.NewDryRunFileStrategy(logger))
 	default:
-		strategies = append(strategies, exec.NewSaveFileStrategy(logger))
+		recorder := ff.manifest.Recorder(action)
+		strategies = append(strategies,
+			exec.NewManifestFileStrategy(recorder, entity.Empty),
+			exec.NewUpdateFileStrategy(ff.updater),
+			exec.NewSaveFileStrategy(ff.modes, ff.policy, ff.journal, recorder, logger),
+		)
 	}
 	executor := exec.NewFilesExecutor(producers, strategies)
 
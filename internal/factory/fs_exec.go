@@ -10,20 +10,33 @@ import (
 type FsModifyExecFactory struct {
 	templateData    map[string]any
 	templateOptions []string
+	skipExt         []string
+	modes           entity.FileModes
+	journal         *exec.Journal
+	manifest        *exec.Manifest
 }
 
 func NewFsModifyExecFactory(
 	templateData map[string]any,
 	templateOptions []string,
+	skipExt []string,
+	modes entity.FileModes,
+	journal *exec.Journal,
+	manifest *exec.Manifest,
 ) *FsModifyExecFactory {
 	return &FsModifyExecFactory{
 		templateData:    templateData,
 		templateOptions: templateOptions,
+		skipExt:         skipExt,
+		modes:           modes,
+		journal:         journal,
+		manifest:        manifest,
 	}
 }
 
 func (f FsModifyExecFactory) Create(
-	dirs []string,
+	action string,
+	dirs []entity.FsDir,
 	logger entity.Logger,
 	dryRun bool,
 ) (entity.Executor, error) {
@@ -32,17 +45,34 @@ func (f FsModifyExecFactory) Create(
 		return nil, nil
 	}
 
-	dirSet := slices.Compact(dirs)
+	dirSet := slices.CompactFunc(dirs, func(a, b entity.FsDir) bool {
+		return a.Path == b.Path
+	})
 
-	if dryRun {
-		return exec.NewDirExecutor(dirSet, []entity.DirStrategy{exec.NewDryRunFileSystemModifyStrategy(logger)}), nil
+	executors := make([]entity.Executor, 0, len(dirSet))
+	for _, dir := range dirSet {
+		paths := []string{dir.Path}
+		if dryRun {
+			executors = append(executors,
+				exec.NewDirExecutor(paths, []entity.DirStrategy{exec.NewDryRunFileSystemModifyStrategy(logger)}),
+			)
+			continue
+		}
+		executors = append(executors,
+			exec.NewDirExecutor(paths, []entity.DirStrategy{
+				exec.NewFileSystemModifyStrategy(
+					dir.Include,
+					dir.Exclude,
+					f.templateData,
+					entity.TemplateFnsMap,
+					f.templateOptions,
+					f.skipExt,
+					f.modes,
+					f.journal,
+					f.manifest.Recorder(action),
+					logger),
+			}),
+		)
 	}
-
-	return exec.NewDirExecutor(dirSet, []entity.DirStrategy{
-		exec.NewFileSystemModifyStrategy(
-			f.templateData,
-			entity.TemplateFnsMap,
-			f.templateOptions,
-			logger),
-	}), nil
+	return exec.NewChain(executors), nil
 }
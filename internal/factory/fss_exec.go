@@ -8,19 +8,35 @@ import (
 type FsSaveExecFactory struct {
 	templateData    map[string]any
 	templateOptions []string
+	skipExt         []string
+	modes           entity.FileModes
+	policy          *exec.OnExistsPolicy
+	journal         *exec.Journal
+	manifest        *exec.Manifest
 }
 
 func NewFsSaveExecFactory(
 	templateData map[string]any,
 	templateOptions []string,
+	skipExt []string,
+	modes entity.FileModes,
+	policy *exec.OnExistsPolicy,
+	journal *exec.Journal,
+	manifest *exec.Manifest,
 ) *FsSaveExecFactory {
 	return &FsSaveExecFactory{
 		templateData:    templateData,
 		templateOptions: templateOptions,
+		skipExt:         skipExt,
+		modes:           modes,
+		policy:          policy,
+		journal:         journal,
+		manifest:        manifest,
 	}
 }
 
 func (f FsSaveExecFactory) Create(
+	action string,
 	fsList []entity.TargetFs,
 	logger entity.Logger,
 	dryRun bool,
@@ -36,9 +52,15 @@ func (f FsSaveExecFactory) Create(
 			fsStrategyBydDir[targetFs.TargetDir],
 			exec.NewFileSystemSaveStrategy(
 				targetFs.Fs,
+				targetFs.Raw,
 				f.templateData,
 				entity.TemplateFnsMap,
 				f.templateOptions,
+				f.skipExt,
+				f.modes,
+				f.policy,
+				f.journal,
+				f.manifest.Recorder(action),
 				logger),
 		)
 	}
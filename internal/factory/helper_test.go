@@ -0,0 +1,28 @@
+package factory
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+// MockLogger collects the formatted info messages.
+type MockLogger struct {
+	entity.Logger
+
+	mx    sync.Mutex
+	infos []string
+}
+
+func (m *MockLogger) Infof(format string, args ...any) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	m.infos = append(m.infos, fmt.Sprintf(format, args...))
+}
+
+func (m *MockLogger) Infos() []string {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	return append([]string(nil), m.infos...)
+}
@@ -0,0 +1,91 @@
+package factory
+
+import (
+	"golang.org/x/xerrors"
+
+	"github.com/kozmod/progen/internal/config"
+	"github.com/kozmod/progen/internal/entity"
+	"github.com/kozmod/progen/internal/exec"
+)
+
+// NewHooks creates the executors of the whole execution hooks. Commands of the `on_failure` hook are processed
+// as templates with the failure data: `.progen.failure.action` and `.progen.failure.error`.
+func NewHooks(
+	hooks entity.Hooks,
+	templateData map[string]any,
+	templateOptions []string,
+	condition *ActionCondition,
+	logger entity.Logger,
+	dryRun bool,
+) (exec.Hooks, error) {
+	beforeAll, err := newHookExecutor(hooks.BeforeAll, condition, logger, dryRun)
+	if err != nil {
+		return exec.Hooks{}, xerrors.Errorf("before all hook: %w", err)
+	}
+	afterAll, err := newHookExecutor(hooks.AfterAll, condition, logger, dryRun)
+	if err != nil {
+		return exec.Hooks{}, xerrors.Errorf("after all hook: %w", err)
+	}
+
+	res := exec.Hooks{
+		BeforeAll: beforeAll,
+		AfterAll:  afterAll,
+	}
+	if len(hooks.OnFailure) == 0 {
+		return res, nil
+	}
+	res.OnFailure = func(action string, failure error) (entity.Executor, error) {
+		data := entity.MergeKeys(make(map[string]any), templateData)
+		data = entity.MergeKeys(data, map[string]any{
+			config.TemplateDataNamespace: map[string]any{
+				config.TemplateDataFailure: map[string]any{
+					config.TemplateDataAction: action,
+					config.TemplateDataError:  failure.Error(),
+				},
+			},
+		})
+		cmds, err := renderCommands(hooks.OnFailure, entity.NewTemplateProc(data, entity.TemplateFnsMap, templateOptions))
+		if err != nil {
+			return nil, xerrors.Errorf("on failure hook: %w", err)
+		}
+		return newHookExecutor(cmds, condition, logger, dryRun)
+	}
+	return res, nil
+}
+
+// newHookExecutor creates the executor of the hook's commands, nil when the hook is empty.
+func newHookExecutor(cmds []entity.Command, condition *ActionCondition, logger entity.Logger, dryRun bool) (entity.Executor, error) {
+	if len(cmds) == 0 {
+		return nil, nil
+	}
+	return NewRunCommandExecutorFactory(condition).Create(entity.Empty, cmds, logger, dryRun)
+}
+
+// renderCommands processes the commands' templates, `when` expressions are evaluated to `true` or `false`.
+func renderCommands(cmds []entity.Command, proc *entity.TmplProc) ([]entity.Command, error) {
+	const name = "hook"
+	res := make([]entity.Command, len(cmds))
+	for i, cmd := range cmds {
+		var err error
+		if cmd.Cmd, err = proc.Process(name, cmd.Cmd); err != nil {
+			return nil, err
+		}
+		if cmd.Dir, err = proc.Process(name, cmd.Dir); err != nil {
+			return nil, err
+		}
+		args := make([]string, len(cmd.Args))
+		for j, arg := range cmd.Args {
+			if args[j], err = proc.Process(name, arg); err != nil {
+				return nil, err
+			}
+		}
+		cmd.Args = args
+		if cmd.When != entity.Empty {
+			if cmd.When, err = proc.Process(name, "{{ if "+cmd.When+" }}true{{ else }}false{{ end }}"); err != nil {
+				return nil, err
+			}
+		}
+		res[i] = cmd
+	}
+	return res, nil
+}
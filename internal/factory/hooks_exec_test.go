@@ -0,0 +1,86 @@
+package factory
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kozmod/progen/internal/entity"
+	"github.com/kozmod/progen/internal/exec"
+)
+
+type mockExecutor struct {
+	err error
+}
+
+func (m mockExecutor) Exec() error {
+	return m.err
+}
+
+func Test_NewHooks(t *testing.T) {
+	t.Parallel()
+
+	var (
+		templateData = map[string]any{"name": "app"}
+		hooks        = entity.Hooks{
+			BeforeAll: []entity.Command{{Cmd: "echo", Args: []string{"before"}}},
+			AfterAll:  []entity.Command{{Cmd: "echo", Args: []string{"after"}}},
+			OnFailure: []entity.Command{
+				{Cmd: "echo", Args: []string{"{{ .name }}: {{ .progen.failure.action }}: {{ .progen.failure.error }}"}},
+				{Cmd: "echo", Args: []string{"action failed"}, When: `ne .progen.failure.action ""`},
+			},
+		}
+	)
+
+	t.Run("success_on_failure_invoked_with_failure_data", func(t *testing.T) {
+		logger := &MockLogger{}
+		res, err := NewHooks(hooks, templateData, nil, nil, logger, true)
+		assert.NoError(t, err)
+
+		chain := exec.NewPreprocessingChain(nil, res, []entity.Executor{
+			exec.NewActionExecutor("cmd_go", mockExecutor{err: errors.New("exit status 1")}, nil, nil),
+			exec.NewActionExecutor("cmd_next", mockExecutor{}, nil, nil),
+		})
+		err = chain.Exec()
+		assert.ErrorContains(t, err, "action [cmd_go]: exit status 1")
+		assert.Equal(t, []string{
+			"execute [dir: .]: echo before",
+			"execute [dir: .]: echo app: cmd_go: exit status 1",
+			"execute [dir: .]: echo action failed",
+		}, logger.Infos())
+	})
+	t.Run("success_on_failure_not_invoked", func(t *testing.T) {
+		logger := &MockLogger{}
+		res, err := NewHooks(hooks, templateData, nil, nil, logger, true)
+		assert.NoError(t, err)
+
+		err = exec.NewPreprocessingChain(nil, res, []entity.Executor{mockExecutor{}}).Exec()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"execute [dir: .]: echo before", "execute [dir: .]: echo after"}, logger.Infos())
+	})
+	t.Run("success_on_failure_without_action", func(t *testing.T) {
+		var (
+			logger    = &MockLogger{}
+			condition = NewActionCondition(templateData, nil, logger)
+		)
+		res, err := NewHooks(entity.Hooks{OnFailure: hooks.OnFailure}, templateData, nil, condition, logger, true)
+		assert.NoError(t, err)
+		assert.Nil(t, res.BeforeAll)
+		assert.Nil(t, res.AfterAll)
+
+		hook, err := res.OnFailure(entity.Empty, errors.New("preprocess"))
+		assert.NoError(t, err)
+		assert.NoError(t, hook.Exec())
+		// the `when` of the second command is false
+		assert.Equal(t, []string{
+			"entry will be skipped: [echo action failed]: `when` is false [false]",
+			"execute [dir: .]: echo app: : preprocess",
+		}, logger.Infos())
+	})
+	t.Run("success_empty_hooks", func(t *testing.T) {
+		res, err := NewHooks(entity.Hooks{}, templateData, nil, nil, &MockLogger{}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, exec.Hooks{}, res)
+	})
+}
@@ -0,0 +1,54 @@
+package factory
+
+import (
+	"os"
+
+	"github.com/kozmod/progen/internal/entity"
+	"github.com/kozmod/progen/internal/exec"
+)
+
+// LinksExecutorFactory creates the executor of the links which `when` expressions are true.
+type LinksExecutorFactory struct {
+	condition *ActionCondition
+	dirMode   os.FileMode
+	policy    *exec.OnExistsPolicy
+	journal   *exec.Journal
+	manifest  *exec.Manifest
+}
+
+func NewLinksExecutorFactory(
+	condition *ActionCondition,
+	dirMode os.FileMode,
+	policy *exec.OnExistsPolicy,
+	journal *exec.Journal,
+	manifest *exec.Manifest,
+) *LinksExecutorFactory {
+	return &LinksExecutorFactory{
+		condition: condition,
+		dirMode:   dirMode,
+		policy:    policy,
+		journal:   journal,
+		manifest:  manifest,
+	}
+}
+
+func (f *LinksExecutorFactory) Create(action string, links []entity.Link, logger entity.Logger, dryRun bool) (entity.Executor, error) {
+	links, err := FilterEntries(f.condition, links, func(link entity.Link) (string, string) {
+		return link.Path, link.When
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(links) == 0 {
+		logger.Infof("links executor: `links` section is empty")
+		return nil, nil
+	}
+
+	if dryRun {
+		return exec.NewLinksExecutor(links, []entity.LinkStrategy{exec.NewDryRunLinkStrategy(logger)}), nil
+	}
+
+	return exec.NewLinksExecutor(links, []entity.LinkStrategy{
+		exec.NewLinkStrategy(f.dirMode, f.policy, f.journal, f.manifest.Recorder(action), logger),
+	}), nil
+}
@@ -0,0 +1,37 @@
+package factory
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kozmod/progen/internal/config"
+	"github.com/kozmod/progen/internal/entity"
+	"github.com/kozmod/progen/internal/exec"
+)
+
+// NewPrompter creates the prompter which asks the answers only when the stdin is a terminal and the input is enabled.
+func NewPrompter(noInput, readStdin bool) *config.Prompter {
+	interactive := !noInput && !readStdin && isTerminal(os.Stdin)
+	return config.NewPrompter(os.Stdin, os.Stderr, interactive)
+}
+
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+// NewOnExistsPolicy creates the policy of the existing files, the `prompt` policy asks the policy by the prompter
+// (`skip` is selected when the input is disabled).
+func NewOnExistsPolicy(def entity.OnExists, prompter *config.Prompter) *exec.OnExistsPolicy {
+	return exec.NewOnExistsPolicy(def, func(path string) (entity.OnExists, error) {
+		answer, err := prompter.Choose(
+			fmt.Sprintf("file exists [%s]", path),
+			exec.OnExistsPromptOptions,
+			string(entity.OnExistsSkip),
+		)
+		return entity.OnExists(answer), err
+	})
+}
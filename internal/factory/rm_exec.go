@@ -7,7 +7,22 @@ import (
 	"github.com/kozmod/progen/internal/exec"
 )
 
-func NewRmExecutor(paths []string, logger entity.Logger, dryRun bool) (entity.Executor, error) {
+func NewRmExecutor(_ string, paths []string, logger entity.Logger, dryRun bool) (entity.Executor, error) {
+	return NewRmExecutorFactory(nil).Create(entity.Empty, paths, logger, dryRun)
+}
+
+// RmExecutorFactory creates the rm executor, which records the removed paths to the journal.
+type RmExecutorFactory struct {
+	journal *exec.Journal
+}
+
+func NewRmExecutorFactory(journal *exec.Journal) *RmExecutorFactory {
+	return &RmExecutorFactory{
+		journal: journal,
+	}
+}
+
+func (f *RmExecutorFactory) Create(_ string, paths []string, logger entity.Logger, dryRun bool) (entity.Executor, error) {
 	if len(paths) == 0 {
 		logger.Infof("rm executor: `rm` section is empty")
 		return nil, nil
@@ -19,5 +34,5 @@ func NewRmExecutor(paths []string, logger entity.Logger, dryRun bool) (entity.Ex
 		return exec.NewRmAllExecutor(pathsSet, []entity.RmStrategy{exec.NewDryRmAllStrategy(logger)}), nil
 	}
 
-	return exec.NewRmAllExecutor(pathsSet, []entity.RmStrategy{exec.NewRmAllStrategy(logger)}), nil
+	return exec.NewRmAllExecutor(pathsSet, []entity.RmStrategy{exec.NewRmAllStrategy(f.journal, logger)}), nil
 }
@@ -23,11 +23,32 @@ const (
 	flagKeyDryRun                      = "dr"
 	flagKeyVersion                     = "version"
 	flagKeyTemplateVariables           = "tvar"
+	flagKeyTemplateVariablesFile       = "tvarfile"
 	flagKeyApplicationWorkingDirectory = "awd"
 	flagKeySkip                        = "skip"
 	flagKeyPreprocessingAllFiles       = "pf"
 	flagKeyMissingKey                  = "missingkey"
 	flagKeyGroup                       = "gp"
+	flagKeyStrict                      = "strict"
+	flagKeyFormat                      = "format"
+	flagKeySchema                      = "schema"
+	flagKeyProfile                     = "profile"
+	flagKeyNoInput                     = "no-input"
+	flagKeyAnswers                     = "answers"
+	flagKeyJobs                        = "jobs"
+	flagKeyAtomic                      = "atomic"
+	flagKeyForce                       = "force"
+	flagKeyReject                      = "rej"
+	flagKeyManifest                    = "manifest"
+	flagKeyOnExists                    = "on-exists"
+)
+
+// Commands declared by the first argument.
+const (
+	CommandValidate = "validate"
+	CommandClean    = "clean"
+	CommandVerify   = "verify"
+	CommandUpdate   = "update"
 )
 
 var (
@@ -38,8 +59,11 @@ type DefaultFlags struct {
 	Verbose              bool
 	DryRun               bool
 	TemplateVars         TemplateVarsFlag
+	TemplateVarFiles     TemplateVarFilesFlag
 	MissingKey           MissingKeyFlag
+	OnExists             OnExistsFlag
 	PrintErrorStackTrace bool
+	Jobs                 int
 }
 
 type Flags struct {
@@ -53,6 +77,17 @@ type Flags struct {
 	PreprocessFiles      bool
 	Group                GroupFlag
 	PrintProcessedConfig bool
+	Strict               bool
+	Format               FormatFlag
+	Schema               bool
+	Profiles             ProfileFlag
+	NoInput              bool
+	AnswersPath          string
+	Atomic               bool
+	Force                bool
+	Reject               bool
+	Manifest             bool
+	Command              string
 }
 
 func (f *Flags) FileLocationMessage() string {
@@ -100,6 +135,27 @@ func NewDefaultFlags(fs *flag.FlagSet) *DefaultFlags {
 		flagKeyDryRun,
 		false,
 		`dry run mode (can be combine with "-v")`)
+	fs.IntVar(
+		&f.Jobs,
+		flagKeyJobs,
+		1,
+		"maximum number of concurrently executed actions: the parallel actions and the actions with depends_on are executed concurrently when it is greater than 1")
+	fs.Var(
+		&f.TemplateVars,
+		flagKeyTemplateVariables,
+		fmt.Sprintf(
+			"template variable `<path>[:<type>]=<value>` (override config variables tree), types: %v, %v, %v, %v, %v",
+			VarTypeString,
+			VarTypeInt,
+			VarTypeFloat,
+			VarTypeBool,
+			VarTypeJSON,
+		),
+	)
+	fs.Var(
+		&f.TemplateVarFiles,
+		flagKeyTemplateVariablesFile,
+		"YAML/JSON file of template variables (merged in order before template variables flags)")
 	fs.Var(
 		&f.MissingKey,
 		flagKeyMissingKey,
@@ -111,9 +167,35 @@ func NewDefaultFlags(fs *flag.FlagSet) *DefaultFlags {
 			entity.MissingKeyError,
 		),
 	)
+	fs.Var(
+		&f.OnExists,
+		flagKeyOnExists,
+		fmt.Sprintf(
+			"policy of the existing files (override settings.on_exists): %v, %v, %v, %v, %v, %v, %v (default: %v)",
+			entity.OnExistsOverwrite,
+			entity.OnExistsSkip,
+			entity.OnExistsError,
+			entity.OnExistsBackup,
+			entity.OnExistsAppend,
+			entity.OnExistsPrepend,
+			entity.OnExistsPrompt,
+			entity.OnExistsOverwrite,
+		),
+	)
 	return &f
 }
 
+// Vars returns the template variables: variables files merged in order and overridden by the template variables flags.
+// The variables files are read relative to the current working directory.
+func (f *DefaultFlags) Vars() (map[string]any, error) {
+	files, err := f.TemplateVarFiles.Load()
+	if err != nil {
+		return nil, err
+	}
+	vars := entity.MergeKeys(make(map[string]any), files)
+	return entity.MergeKeys(vars, f.TemplateVars.Vars), nil
+}
+
 func NewFlags(fs *flag.FlagSet) *Flags {
 	var (
 		f = Flags{DefaultFlags: NewDefaultFlags(fs)}
@@ -152,15 +234,80 @@ func NewFlags(fs *flag.FlagSet) *Flags {
 		flagKeyGroup,
 		"list of executing groups",
 	)
+	fs.BoolVar(
+		&f.Strict,
+		flagKeyStrict,
+		false,
+		"strict mode: reject unknown root tags and fields")
+	fs.Var(
+		&f.Profiles,
+		flagKeyProfile,
+		"list of applying profiles (in order)",
+	)
+	fs.BoolVar(
+		&f.NoInput,
+		flagKeyNoInput,
+		false,
+		"disable prompts: use default answers and fail when required answers are missing")
+	fs.StringVar(
+		&f.AnswersPath,
+		flagKeyAnswers,
+		entity.Empty,
+		"answers file path to re-apply the saved variables")
+	fs.BoolVar(
+		&f.Atomic,
+		flagKeyAtomic,
+		false,
+		"atomic mode: roll back the changes of the file system when the execution fails")
+	fs.BoolVar(
+		&f.Force,
+		flagKeyForce,
+		false,
+		"clean command: remove the modified files")
+	fs.BoolVar(
+		&f.Manifest,
+		flagKeyManifest,
+		false,
+		"write the manifest of the created files and their rendered content to .progen dir (always written by update command)")
+	fs.BoolVar(
+		&f.Reject,
+		flagKeyReject,
+		false,
+		"update command: write the conflicts to .rej files instead of the conflict markers")
+	fs.BoolVar(
+		&f.Schema,
+		flagKeySchema,
+		false,
+		"output JSON Schema of the config")
+	fs.Var(
+		&f.Format,
+		flagKeyFormat,
+		fmt.Sprintf(
+			"configuration format: %v, %v, %v (default: by file extension)",
+			entity.ConfigFormatYAML,
+			entity.ConfigFormatJSON,
+			entity.ConfigFormatTOML,
+		),
+	)
 
 	return &f
 }
 
 func (f *Flags) Parse(fs *flag.FlagSet, args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case CommandValidate, CommandClean, CommandVerify, CommandUpdate:
+			f.Command, args = args[0], args[1:]
+		}
+	}
+
 	err := fs.Parse(args)
 	if err != nil {
 		return xerrors.Errorf("parse args: %w", err)
 	}
+	if f.Jobs < 1 {
+		return xerrors.Errorf("[%s] must be greater than 0: %d", flagKeyJobs, f.Jobs)
+	}
 
 	for i, arg := range args {
 		if strings.TrimSpace(arg) == entity.Dash {
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -151,6 +153,83 @@ func Test_TemplateVarsFlag(t *testing.T) {
 			map[string]any{"var": map[string]any{"X": "SOME=x=y"}},
 			vars.Vars))
 	})
+	t.Run("success_parse_typed_variables", func(t *testing.T) {
+		var (
+			fs   = flag.NewFlagSet(setName, flag.ContinueOnError)
+			vars TemplateVarsFlag
+		)
+		fs.Var(&vars, flagName, usage)
+
+		err := fs.Parse([]string{
+			flagKey, ".var.port:int=8080",
+			flagKey, ".var.ratio:float=0.5",
+			flagKey, ".var.grpc:bool=false",
+			flagKey, ".var.host:string=some.com",
+			flagKey, `.var.list:json=["a", 1]`,
+			flagKey, `.var.obj:json={"a": {"b": true}}`,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t,
+			map[string]any{"var": map[string]any{
+				"port":  8080,
+				"ratio": 0.5,
+				"grpc":  false,
+				"host":  "some.com",
+				"list":  []any{"a", 1},
+				"obj":   map[string]any{"a": map[string]any{"b": true}},
+			}},
+			vars.Vars)
+	})
+	t.Run("error_when_typed_value_is_invalid", func(t *testing.T) {
+		for _, in := range []string{".var.port:int=a", ".var.a:bool=1a", ".var.a:json={", ".var.a:uint=1", ":int=1"} {
+			var vars TemplateVarsFlag
+			assert.Error(t, vars.Set(in), in)
+		}
+	})
+}
+
+func Test_TemplateVarFilesFlag(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success_merge_files_in_order", func(t *testing.T) {
+		var (
+			dir   = t.TempDir()
+			yml   = filepath.Join(dir, "vars.yml")
+			jsn   = filepath.Join(dir, "vars.json")
+			flags DefaultFlags
+		)
+		assert.NoError(t, os.WriteFile(yml, []byte("vars:\n  a: 1\n  b: yml\n"), os.ModePerm))
+		assert.NoError(t, os.WriteFile(jsn, []byte(`{"vars": {"b": "json", "c": [1, 2]}}`), os.ModePerm))
+
+		assert.NoError(t, flags.TemplateVarFiles.Set(yml))
+		assert.NoError(t, flags.TemplateVarFiles.Set(jsn))
+		assert.NoError(t, flags.TemplateVars.Set(".vars.c:int=3"))
+		assert.Equal(t, []string{yml, jsn}, flags.TemplateVarFiles.Paths)
+
+		vars, err := flags.Vars()
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"vars": map[string]any{"a": 1, "b": "json", "c": 3}}, vars)
+	})
+	t.Run("success_load_files_after_set", func(t *testing.T) {
+		var (
+			dir  = t.TempDir()
+			path = filepath.Join(dir, "vars.yml")
+			vars TemplateVarFilesFlag
+		)
+		// the file is read by Load (after the change of the working directory), not by Set
+		assert.NoError(t, vars.Set(path))
+		assert.NoError(t, os.WriteFile(path, []byte("vars:\n  a: 1\n"), os.ModePerm))
+
+		loaded, err := vars.Load()
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"vars": map[string]any{"a": 1}}, loaded)
+	})
+	t.Run("error_when_file_not_exists", func(t *testing.T) {
+		var vars TemplateVarFilesFlag
+		assert.NoError(t, vars.Set(filepath.Join(t.TempDir(), "vars.yml")))
+		_, err := vars.Load()
+		assert.Error(t, err)
+	})
 }
 
 // nolint: dupl
@@ -298,6 +377,7 @@ func Test_parseFlags(t *testing.T) {
 		f           = dashPrefixFn(flagKeyConfigFile)
 		pf          = dashPrefixFn(flagKeyPreprocessingAllFiles)
 		missingkey  = dashPrefixFn(flagKeyMissingKey)
+		onExists    = dashPrefixFn(flagKeyOnExists)
 	)
 
 	t.Run("success", func(t *testing.T) {
@@ -314,6 +394,7 @@ func Test_parseFlags(t *testing.T) {
 					DryRun:               true,
 					Verbose:              true,
 					PrintErrorStackTrace: true,
+					Jobs:                 1,
 				},
 				PrintProcessedConfig: true,
 				PreprocessFiles:      true,
@@ -334,6 +415,7 @@ func Test_parseFlags(t *testing.T) {
 				DefaultFlags: &DefaultFlags{
 					Verbose: true,
 					DryRun:  true,
+					Jobs:    1,
 				},
 				PreprocessFiles: true,
 				ConfigPath:      configPath,
@@ -355,6 +437,7 @@ func Test_parseFlags(t *testing.T) {
 				DefaultFlags: &DefaultFlags{
 					Verbose: true,
 					DryRun:  true,
+					Jobs:    1,
 				},
 				PreprocessFiles: true,
 				ConfigPath:      configPath,
@@ -363,6 +446,78 @@ func Test_parseFlags(t *testing.T) {
 			},
 			*flags)
 	})
+	t.Run("success_with_validate_command", func(t *testing.T) {
+		var (
+			testFs = flag.NewFlagSet(fsName, flag.ContinueOnError)
+
+			flags = NewFlags(testFs)
+		)
+		err := flags.Parse(testFs, []string{CommandValidate, v, f, configPath})
+		assert.NoError(t, err)
+		assert.Equal(t,
+			Flags{
+				DefaultFlags: &DefaultFlags{
+					Verbose: true,
+					Jobs:    1,
+				},
+				PreprocessFiles: true,
+				ConfigPath:      configPath,
+				AWD:             dot,
+				Command:         CommandValidate,
+			},
+			*flags)
+	})
+	t.Run("success_with_clean_command_and_force", func(t *testing.T) {
+		var (
+			testFs = flag.NewFlagSet(fsName, flag.ContinueOnError)
+
+			flags = NewFlags(testFs)
+		)
+		err := flags.Parse(testFs, []string{CommandClean, "-" + flagKeyForce})
+		assert.NoError(t, err)
+		assert.Equal(t, CommandClean, flags.Command)
+		assert.True(t, flags.Force)
+	})
+	t.Run("success_with_template_vars_and_no_input", func(t *testing.T) {
+		var (
+			testFs = flag.NewFlagSet(fsName, flag.ContinueOnError)
+
+			flags = NewFlags(testFs)
+		)
+		err := flags.Parse(testFs, []string{"-tvar", ".vars.name=svc", "-" + flagKeyNoInput})
+		assert.NoError(t, err)
+		assert.Equal(t,
+			Flags{
+				DefaultFlags: &DefaultFlags{
+					TemplateVars: TemplateVarsFlag{Vars: map[string]any{"vars": map[string]any{"name": "svc"}}},
+					Jobs:         1,
+				},
+				PreprocessFiles: true,
+				ConfigPath:      configPath,
+				AWD:             dot,
+				NoInput:         true,
+			},
+			*flags)
+	})
+	t.Run("success_with_jobs", func(t *testing.T) {
+		var (
+			testFs = flag.NewFlagSet(fsName, flag.ContinueOnError)
+
+			flags = NewFlags(testFs)
+		)
+		err := flags.Parse(testFs, []string{"-" + flagKeyJobs, "4"})
+		assert.NoError(t, err)
+		assert.Equal(t, 4, flags.Jobs)
+	})
+	t.Run("error_when_jobs_not_positive", func(t *testing.T) {
+		var (
+			testFs = flag.NewFlagSet(fsName, flag.ContinueOnError)
+
+			flags = NewFlags(testFs)
+		)
+		err := flags.Parse(testFs, []string{"-" + flagKeyJobs, "0"})
+		assert.Error(t, err)
+	})
 	t.Run("error_when_flag_not_specified", func(t *testing.T) {
 		var (
 			testFs = flag.NewFlagSet(fsName, flag.ContinueOnError)
@@ -401,6 +556,7 @@ func Test_parseFlags(t *testing.T) {
 				DefaultFlags: &DefaultFlags{
 					Verbose: false,
 					DryRun:  false,
+					Jobs:    1,
 				},
 				PreprocessFiles: false,
 				ConfigPath:      configPath,
@@ -429,6 +585,7 @@ func Test_parseFlags(t *testing.T) {
 						Verbose:    false,
 						DryRun:     false,
 						MissingKey: MissingKeyFlag(missingKeyValueDefault),
+						Jobs:       1,
 					},
 					PreprocessFiles: true,
 					ConfigPath:      configPath,
@@ -457,6 +614,34 @@ func Test_parseFlags(t *testing.T) {
 			assert.Error(t, err)
 		})
 	})
+	t.Run("on-exists", func(t *testing.T) {
+		t.Run("success_with_set_skip", func(t *testing.T) {
+			var (
+				testFs = flag.NewFlagSet(fsName, flag.ContinueOnError)
+
+				flags = NewFlags(testFs)
+			)
+
+			err := flags.Parse(testFs, []string{fmt.Sprintf("%s=%v", onExists, entity.OnExistsSkip)})
+			assert.NoError(t, err)
+			assert.Equal(t, OnExistsFlag(entity.OnExistsSkip), flags.OnExists)
+		})
+		t.Run("error_with_set_unexpected", func(t *testing.T) {
+			var (
+				testFs = flag.NewFlagSet(fsName, flag.ContinueOnError)
+
+				flags = NewFlags(testFs)
+			)
+
+			testFs.SetOutput(MockWriter{
+				assertWriteFn: func(p []byte) {
+					assert.NotEmpty(t, p)
+				},
+			})
+			err := flags.Parse(testFs, []string{fmt.Sprintf("%s=%v", onExists, "xxx_unexpected_xxx")})
+			assert.Error(t, err)
+		})
+	})
 }
 
 type MockWriter struct {
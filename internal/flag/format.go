@@ -0,0 +1,31 @@
+package flag
+
+import (
+	"strings"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+type FormatFlag string
+
+func (s *FormatFlag) String() string {
+	if s == nil {
+		return entity.Empty
+	}
+	return string(*s)
+}
+
+func (s *FormatFlag) Set(value string) error {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == entity.Empty {
+		*s = FormatFlag(entity.Empty)
+		return nil
+	}
+
+	if err := entity.ConfigFormat(value).Valid(); err != nil {
+		return err
+	}
+
+	*s = FormatFlag(value)
+	return nil
+}
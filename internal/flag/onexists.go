@@ -0,0 +1,26 @@
+package flag
+
+import (
+	"strings"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+type OnExistsFlag string
+
+func (s *OnExistsFlag) String() string {
+	if s == nil {
+		return entity.Empty
+	}
+	return string(*s)
+}
+
+func (s *OnExistsFlag) Set(value string) error {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if err := entity.OnExists(value).Valid(); err != nil {
+		return err
+	}
+
+	*s = OnExistsFlag(value)
+	return nil
+}
@@ -0,0 +1,27 @@
+package flag
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kozmod/progen/internal/entity"
+)
+
+type ProfileFlag []string
+
+func (s *ProfileFlag) String() string {
+	type alias struct {
+		profiles []string
+	}
+	a := alias{profiles: *s}
+	return fmt.Sprintf("%v", a.profiles)
+}
+
+func (s *ProfileFlag) Set(value string) error {
+	value = strings.TrimSpace(value)
+	if value == entity.Empty {
+		return nil
+	}
+	*s = append(*s, value)
+	return nil
+}
@@ -1,14 +1,29 @@
 package flag
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 
 	"golang.org/x/xerrors"
+	yaml "gopkg.in/yaml.v3"
 
 	"github.com/kozmod/progen/internal/entity"
 )
 
+// Types of the template variables declared as `<path>:<type>=<value>`.
+const (
+	VarTypeString = "string"
+	VarTypeInt    = "int"
+	VarTypeFloat  = "float"
+	VarTypeBool   = "bool"
+	VarTypeJSON   = "json"
+
+	varTypeSep = ":"
+)
+
 var (
 	ErrVariableNotSet = fmt.Errorf("value not set: mast be separated by [%s]", entity.EqualsSign)
 )
@@ -21,42 +36,88 @@ func (v *TemplateVarsFlag) String() string {
 	return fmt.Sprintf("%v", v.Vars)
 }
 
+// Set parses the variable `<path>[:<type>]=<value>` (`.vars.port:int=8080`) and sets it to the variables tree.
 func (v *TemplateVarsFlag) Set(s string) error {
-	if v.Vars == nil {
-		v.Vars = make(map[string]any)
+	if strings.TrimSpace(s) == entity.Empty {
+		return nil
+	}
+
+	key, raw, found := strings.Cut(s, entity.EqualsSign)
+	if !found {
+		return xerrors.Errorf("%w", ErrVariableNotSet)
+	}
+
+	path, typ, _ := strings.Cut(key, varTypeSep)
+	if len(entity.VarPath(path)) == 0 {
+		return xerrors.Errorf("variable path is empty: %s", s)
+	}
+
+	val, err := parseVarValue(strings.TrimSpace(typ), raw)
+	if err != nil {
+		return xerrors.Errorf("variable [%s]: %w", path, err)
 	}
+	v.Vars = entity.SetVar(v.Vars, path, val)
+	return nil
+}
 
-	splitVar := strings.Split(s, entity.Dot)
-	current := v.Vars
-	if len(splitVar) > 0 {
-		for i, key := range splitVar {
-			key = strings.TrimSpace(key)
-			if key == entity.Empty {
-				continue
-			}
-
-			if i != len(splitVar)-1 {
-				val, exists := v.Vars[key]
-				if !exists {
-					next := make(map[string]any, 1)
-					current[key] = next
-					current = next
-					continue
-				}
-				if m, ok := val.(map[string]any); ok {
-					current = m
-					continue
-				}
-			}
-
-			keyVal := strings.SplitN(key, entity.EqualsSign, 2)
-			if len(keyVal) < 2 {
-				return xerrors.Errorf("%w", ErrVariableNotSet)
-			}
-
-			current[keyVal[0]] = keyVal[1]
+func parseVarValue(typ, raw string) (any, error) {
+	switch typ {
+	case entity.Empty, VarTypeString:
+		return raw, nil
+	case VarTypeInt:
+		return strconv.Atoi(raw)
+	case VarTypeFloat:
+		return strconv.ParseFloat(raw, 64)
+	case VarTypeBool:
+		return strconv.ParseBool(raw)
+	case VarTypeJSON:
+		if !json.Valid([]byte(raw)) {
+			return nil, xerrors.Errorf("invalid json: %s", raw)
+		}
+		// yaml keeps json integers as `int`
+		var val any
+		if err := yaml.Unmarshal([]byte(raw), &val); err != nil {
+			return nil, xerrors.Errorf("unmarshal json: %w", err)
 		}
+		return val, nil
+	default:
+		return nil, xerrors.Errorf("unknown type [%s]: expected %s, %s, %s, %s, %s",
+			typ, VarTypeString, VarTypeInt, VarTypeFloat, VarTypeBool, VarTypeJSON)
 	}
+}
+
+// TemplateVarFilesFlag collects the paths of YAML/JSON files of the template variables,
+// the files are loaded by [TemplateVarFilesFlag.Load] (after the change of the application working directory).
+type TemplateVarFilesFlag struct {
+	Paths []string
+}
 
+func (v *TemplateVarFilesFlag) String() string {
+	return fmt.Sprintf("%v", v.Paths)
+}
+
+func (v *TemplateVarFilesFlag) Set(path string) error {
+	path = strings.TrimSpace(path)
+	if path == entity.Empty {
+		return nil
+	}
+	v.Paths = append(v.Paths, path)
 	return nil
 }
+
+// Load reads the variables files and merges them in order.
+func (v *TemplateVarFilesFlag) Load() (map[string]any, error) {
+	var res map[string]any
+	for _, path := range v.Paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, xerrors.Errorf("read variables file: %w", err)
+		}
+		var vars map[string]any
+		if err = yaml.Unmarshal(data, &vars); err != nil {
+			return nil, xerrors.Errorf("unmarshal variables file [%s]: %w", path, err)
+		}
+		res = entity.MergeKeys(res, vars)
+	}
+	return res, nil
+}
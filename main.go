@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/go-resty/resty/v2"
@@ -28,17 +29,32 @@ func main() {
 
 	logFatalSuffixFn := entity.NewAppendVPlusOrV(flags.PrintErrorStackTrace)
 
+	if flags.Schema {
+		schema, err := config.Schema()
+		if err != nil {
+			log.Fatalf(logFatalSuffixFn("config schema: "), err)
+		}
+		fmt.Println(string(schema))
+		return
+	}
+
 	logger, err := factory.NewLogger(flags.Verbose)
 	if err != nil {
 		log.Fatalf(logFatalSuffixFn("create logger: "), err)
 	}
+
+	var failed bool
 	defer func() {
 		_ = logger.Sync()
+		if failed && flags.Command != entity.Empty {
+			os.Exit(1)
+		}
 	}()
 
 	{
 		if err = os.Chdir(flags.AWD); err != nil {
 			logger.Errorf(logFatalSuffixFn("changes the application working directory: "), xerrors.Errorf("%w", err))
+			failed = true
 			return
 		}
 
@@ -46,31 +62,77 @@ func main() {
 		awd, err = os.Getwd()
 		if err != nil {
 			logger.Errorf(logFatalSuffixFn("get the application working directory: "), xerrors.Errorf("%w", err))
+			failed = true
 			return
 		}
 		logger.Infof("application working directory: %s", awd)
 	}
 
+	switch flags.Command {
+	case flag.CommandClean, flag.CommandVerify:
+		failed = !manifestCommand(flags, logger, logFatalSuffixFn)
+		return
+	}
+
 	defer func(start time.Time) {
 		logger.Infof("execution time: %v", time.Since(start))
 	}(time.Now())
 
 	logger.Infof("configuration file: %s", flags.FileLocationMessage())
 
-	data, err := config.NewConfigReader(flags).Read()
+	reader := config.NewConfigReader(flags, factory.NewHTTPClient(nil, logger))
+	defer func() {
+		if err := reader.Close(); err != nil {
+			logger.Errorf("remove temporary config files: %v", err)
+		}
+	}()
+
+	data, err := reader.Read()
 	if err != nil {
 		logger.Errorf(logFatalSuffixFn("read config: "), err)
+		failed = true
+		return
+	}
+
+	// variables overrides: answers file <- flags (variables files <- variables)
+	templateVars, err := flags.Vars()
+	if err != nil {
+		logger.Errorf(logFatalSuffixFn("load variables: "), err)
+		failed = true
 		return
 	}
+	if flags.Command == flag.CommandUpdate && flags.AnswersPath == entity.Empty {
+		// the update re-renders the config with the stored variables
+		if _, err = os.Stat(config.AnswersFile); err == nil {
+			flags.AnswersPath = config.AnswersFile
+		}
+	}
+	if flags.AnswersPath != entity.Empty {
+		var answers map[string]any
+		answers, err = config.ReadAnswers(flags.AnswersPath)
+		if err != nil {
+			logger.Errorf(logFatalSuffixFn("read answers: "), err)
+			failed = true
+			return
+		}
+		templateVars = entity.MergeKeys(answers, templateVars)
+		logger.Infof("answers will be applied: %s", flags.AnswersPath)
+	}
 
-	rawConfig, templateData, err := config.NewRawPreprocessor(
-		flags.ConfigPath,
-		flags.TemplateVars.Vars,
+	prompter := factory.NewPrompter(flags.NoInput, flags.ReadStdin)
+	preprocessor := config.NewRawPreprocessor(
+		reader.Path(),
+		config.NewIncludeResolver(reader.Format(), reader.ReadFile),
+		templateVars,
 		entity.TemplateFnsMap,
 		[]string{flags.MissingKey.String()},
-	).Process(data)
+		flags.Profiles,
+		prompter,
+	)
+	rawConfig, templateData, err := preprocessor.Process(data)
 	if err != nil {
 		logger.Errorf(logFatalSuffixFn("preprocess raw config: "), err)
+		failed = true
 		return
 	}
 
@@ -78,58 +140,163 @@ func main() {
 		logger.ForceInfof("preprocessed config:\n%s", string(rawConfig))
 	}
 
+	unmarshaler := config.NewYamlConfigUnmarshaler(flags.Strict)
+	if flags.Command == flag.CommandValidate {
+		// the sections, which are unmarshalled without errors, are validated too
+		conf, errs := unmarshaler.UnmarshalAll(rawConfig)
+		errs = append(errs, conf.ValidateAll()...)
+		for _, err = range errs {
+			logger.Errorf(logFatalSuffixFn("validate config: "), preprocessor.Source().Locate(rawConfig, err))
+		}
+		if failed = len(errs) > 0; !failed {
+			logger.ForceInfof("config is valid: %s", flags.FileLocationMessage())
+		}
+		return
+	}
+
 	var (
 		conf config.Config
 	)
-	conf, err = config.NewYamlConfigUnmarshaler().Unmarshal(rawConfig)
+	conf, err = unmarshaler.Unmarshal(rawConfig)
 	if err != nil {
-		logger.Errorf(logFatalSuffixFn("unmarshal config: "), err)
+		logger.Errorf(logFatalSuffixFn("unmarshal config: "), preprocessor.Source().Locate(rawConfig, err))
+		failed = true
 		return
 	}
 
 	if err = conf.Validate(); err != nil {
-		logger.Errorf(logFatalSuffixFn("validate config: "), err)
+		logger.Errorf(logFatalSuffixFn("validate config: "), preprocessor.Source().Locate(rawConfig, err))
+		failed = true
 		return
 	}
 
+	profiles, err := conf.Settings.Profiles.Select(flags.Profiles)
+	if err != nil {
+		logger.Errorf(logFatalSuffixFn("select profiles: "), err)
+		failed = true
+		return
+	}
+	if len(profiles) > 0 {
+		logger.Infof("profiles will be applied: [%s]", strings.Join(flags.Profiles, entity.LogSliceSep))
+	}
+
 	var (
 		actionFilter = factory.NewActionFilter(
-			flags.Skip,
-			flags.Group,
+			append(flags.Skip, profiles.Skip()...),
+			append(flags.Group, profiles.Groups()...),
 			conf.Settings.Groups.GroupByAction(),
 			conf.Settings.Groups.ManualActions(),
 			logger,
 		)
 		templateOptions = []string{flags.MissingKey.String()}
+		actionCondition = factory.NewActionCondition(templateData, templateOptions, logger)
+		expander        = config.NewExpander(templateData, entity.TemplateFnsMap, templateOptions)
 		preprocessors   = &exec.Preprocessors{}
 	)
 
+	dirActions, err := conf.DirActions(expander)
+	if err != nil {
+		logger.Errorf(logFatalSuffixFn("expand dirs: "), preprocessor.Source().Locate(rawConfig, err))
+		failed = true
+		return
+	}
+	commandActions, err := conf.CommandActions(expander)
+	if err != nil {
+		logger.Errorf(logFatalSuffixFn("expand commands: "), preprocessor.Source().Locate(rawConfig, err))
+		failed = true
+		return
+	}
+	filesActions, err := conf.FilesActions(expander)
+	if err != nil {
+		logger.Errorf(logFatalSuffixFn("expand files: "), preprocessor.Source().Locate(rawConfig, err))
+		failed = true
+		return
+	}
+	linksActions, err := conf.LinksActions(expander)
+	if err != nil {
+		logger.Errorf(logFatalSuffixFn("expand links: "), preprocessor.Source().Locate(rawConfig, err))
+		failed = true
+		return
+	}
+	copyActions, err := conf.CopyActions(expander)
+	if err != nil {
+		logger.Errorf(logFatalSuffixFn("expand copy: "), preprocessor.Source().Locate(rawConfig, err))
+		failed = true
+		return
+	}
+
+	hooks, err := factory.NewHooks(conf.Hooks(), templateData, templateOptions, actionCondition, logger, flags.DryRun)
+	if err != nil {
+		logger.Errorf(logFatalSuffixFn("create hooks: "), err)
+		failed = true
+		return
+	}
+
+	var (
+		journal  *exec.Journal
+		manifest *exec.Manifest
+		updater  *exec.Updater
+		onExists = conf.Settings.OnExists
+		modes    = conf.Settings.FileModes()
+	)
+	if flags.OnExists != entity.Empty {
+		onExists = entity.OnExists(flags.OnExists)
+	}
+	policy := factory.NewOnExistsPolicy(onExists, prompter)
+	if flags.Atomic && !flags.DryRun {
+		journal = exec.NewJournal(logger)
+	}
+	if (flags.Manifest || flags.Command == flag.CommandUpdate) && !flags.DryRun {
+		if manifest, err = exec.OpenManifest(exec.ManifestPath); err != nil {
+			logger.Errorf(logFatalSuffixFn("open manifest: "), err)
+			failed = true
+			return
+		}
+	}
+	if flags.Command == flag.CommandUpdate && !flags.DryRun {
+		updater = exec.NewUpdater(exec.ManifestPath, flags.Reject, journal, logger)
+	}
+
 	procChain, err := factory.NewExecutorChainFactory(
 		logger,
 		flags.DryRun,
+		flags.Jobs,
 		func(executors []entity.Executor) entity.Executor {
-			return exec.NewPreprocessingChain(preprocessors, executors)
+			if journal != nil {
+				executors = []entity.Executor{exec.NewAtomicExecutor(journal, exec.NewChain(executors))}
+			}
+			return exec.NewPreprocessingChain(preprocessors, hooks, executors)
 		},
 		factory.NewExecutorBuilderFactory(
-			conf.DirActions(),
-			factory.NewMkdirExecutor,
+			dirActions,
+			factory.NewMkdirExecutorFactory(actionCondition, modes.Dir, journal, manifest).Create,
 			actionFilter,
+			actionCondition,
 		),
 		factory.NewExecutorBuilderFactory(
 			conf.RmActions(),
-			factory.NewRmExecutor,
+			factory.NewRmExecutorFactory(journal).Create,
 			actionFilter,
+			actionCondition,
 		),
 		factory.NewExecutorBuilderFactory(
-			conf.CommandActions(),
-			factory.NewRunCommandExecutor,
+			commandActions,
+			factory.NewRunCommandExecutorFactory(actionCondition).Create,
 			actionFilter,
+			actionCondition,
 		),
 		factory.NewExecutorBuilderFactory(
-			conf.FilesActions(),
+			reader.Origin().ResolveFiles(filesActions, conf.Settings.HTTP),
 			factory.NewPreprocessorsFileExecutorFactory(
 				templateData,
 				templateOptions,
+				conf.Settings.Template.SkipExt,
+				actionCondition,
+				modes,
+				policy,
+				journal,
+				manifest,
+				updater,
 				flags.PreprocessFiles,
 				preprocessors,
 				func(logger entity.Logger) *resty.Client {
@@ -137,24 +304,126 @@ func main() {
 				},
 			).Create,
 			actionFilter,
+			actionCondition,
 		),
 		factory.NewExecutorBuilderFactory(
 			conf.FsActions(),
 			factory.NewFsModifyExecFactory(
 				templateData,
 				templateOptions,
+				conf.Settings.Template.SkipExt,
+				modes,
+				journal,
+				manifest,
+			).Create,
+			actionFilter,
+			actionCondition,
+		),
+		factory.NewExecutorBuilderFactory(
+			linksActions,
+			factory.NewLinksExecutorFactory(actionCondition, modes.Dir, policy, journal, manifest).Create,
+			actionFilter,
+			actionCondition,
+		),
+		factory.NewExecutorBuilderFactory(
+			copyActions,
+			factory.NewCopyExecFactory(
+				actionCondition,
+				factory.NewFsSaveExecFactory(
+					templateData,
+					templateOptions,
+					conf.Settings.Template.SkipExt,
+					modes,
+					policy,
+					journal,
+					manifest,
+				),
 			).Create,
 			actionFilter,
+			actionCondition,
 		),
 	).Create()
 	if err != nil {
 		logger.Errorf(logFatalSuffixFn("create processors chain: "), err)
+		failed = true
 		return
 	}
 
 	err = procChain.Exec()
 	if err != nil {
 		logger.Errorf(logFatalSuffixFn("execute chain: "), err)
+		// the created artifacts stay when the changes are not rolled back
+		if journal == nil {
+			saveManifest(manifest, logger, logFatalSuffixFn)
+		}
+		failed = true
+		return
+	}
+
+	if !saveManifest(manifest, logger, logFatalSuffixFn) {
+		failed = true
 		return
 	}
+
+	for _, conflict := range updater.Conflicts() {
+		logger.ForceInfof("update: conflict: %s", conflict)
+		failed = true
+	}
+
+	if answers := config.Answers(templateData, conf.Settings.Prompts, templateVars); len(answers) > 0 && !flags.DryRun {
+		if err = config.WriteAnswers(config.AnswersFile, answers); err != nil {
+			logger.Errorf(logFatalSuffixFn("save answers: "), err)
+			failed = true
+			return
+		}
+		logger.Infof("answers saved: %s", config.AnswersFile)
+	}
+}
+
+func saveManifest(manifest *exec.Manifest, logger entity.Logger, logFatalSuffixFn entity.AppendVPlusOrV) bool {
+	if manifest.Len() == 0 {
+		return true
+	}
+	if err := manifest.Save(exec.ManifestPath); err != nil {
+		logger.Errorf(logFatalSuffixFn("save manifest: "), err)
+		return false
+	}
+	logger.Infof("manifest saved: %s", exec.ManifestPath)
+	return true
+}
+
+// manifestCommand executes `clean` or `verify` command, returns false when the command fails.
+func manifestCommand(flags flag.Flags, logger entity.LoggerWrapper, logFatalSuffixFn entity.AppendVPlusOrV) bool {
+	manifest, err := exec.ReadManifest(exec.ManifestPath)
+	if err != nil {
+		logger.Errorf(logFatalSuffixFn(flags.Command+": "), err)
+		return false
+	}
+
+	if flags.Command == flag.CommandClean {
+		skipped, err := manifest.Clean(exec.ManifestPath, flags.Force, logger)
+		if err != nil {
+			logger.Errorf(logFatalSuffixFn("clean: "), err)
+			return false
+		}
+		for _, drift := range skipped {
+			logger.ForceInfof("clean: skip %s file (use -force to remove): %s", drift.Status, drift.Path)
+		}
+		logger.ForceInfof("clean: done: %s", exec.ManifestPath)
+		return true
+	}
+
+	drifts, err := manifest.Verify()
+	if err != nil {
+		logger.Errorf(logFatalSuffixFn("verify: "), err)
+		return false
+	}
+	for _, drift := range drifts {
+		logger.Errorf("verify: %s: %s", drift.Status, drift.Path)
+	}
+	if len(drifts) > 0 {
+		return false
+	}
+	logger.ForceInfof("verify: no drift: %s", exec.ManifestPath)
+	return true
 }
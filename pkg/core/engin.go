@@ -14,10 +14,11 @@ type Engin struct {
 	mx       sync.RWMutex
 	files    []entity.Action[[]entity.UndefinedFile]
 	cmd      []entity.Action[[]entity.Command]
-	dirs     []entity.Action[[]string]
-	fsModify []entity.Action[[]string]
+	dirs     []entity.Action[[]entity.Dir]
+	fsModify []entity.Action[[]entity.FsDir]
 	fsSave   []entity.Action[[]entity.TargetFs]
 	rm       []entity.Action[[]string]
+	links    []entity.Action[[]entity.Link]
 
 	logger entity.Logger
 }
@@ -41,6 +42,7 @@ func (e *Engin) Run(config *Config) error {
 	var (
 		logFatalSuffixFn = entity.NewAppendVPlusOrV(config.PrintErrorStackTrace)
 		actionFilter     factory.DummyActionFilter
+		policy           = factory.NewOnExistsPolicy(entity.OnExists(config.OnExists), factory.NewPrompter(false, false))
 	)
 
 	e.mx.RLock()
@@ -57,50 +59,83 @@ func (e *Engin) Run(config *Config) error {
 		}
 	}
 
+	templateVars, err := config.vars()
+	if err != nil {
+		return xerrors.Errorf("failed to load variables files: %w", err)
+	}
+
 	procChain, err := factory.NewExecutorChainFactory(
 		logger,
 		config.DryRun,
+		config.Jobs,
 		func(executors []entity.Executor) entity.Executor {
 			return exec.NewChain(executors)
 		},
 		factory.NewExecutorBuilderFactory(
 			e.dirs,
-			factory.NewMkdirExecutor,
+			factory.NewMkdirExecutorFactory(nil, 0, nil, nil).Create,
 			actionFilter,
+			nil,
 		),
 		factory.NewExecutorBuilderFactory(
 			e.fsModify,
 			factory.NewFsModifyExecFactory(
-				config.TemplateVars.Vars,
+				templateVars,
 				[]string{config.MissingKey.String()},
+				nil,
+				entity.FileModes{},
+				nil,
+				nil,
 			).Create,
 			actionFilter,
+			nil,
 		),
 		factory.NewExecutorBuilderFactory(
 			e.fsSave,
 			factory.NewFsSaveExecFactory(
-				config.TemplateVars.Vars,
+				templateVars,
 				[]string{config.MissingKey.String()},
+				nil,
+				entity.FileModes{},
+				policy,
+				nil,
+				nil,
 			).Create,
 			actionFilter,
+			nil,
 		),
 		factory.NewExecutorBuilderFactory(
 			e.rm,
 			factory.NewRmExecutor,
 			actionFilter,
+			nil,
 		),
 		factory.NewExecutorBuilderFactory(
 			e.files,
 			factory.NewFileExecutorFactory(
-				config.TemplateVars.Vars,
+				templateVars,
 				[]string{config.MissingKey.String()},
+				nil,
+				entity.FileModes{},
+				policy,
+				nil,
+				nil,
+				nil,
 			).Create,
 			actionFilter,
+			nil,
+		),
+		factory.NewExecutorBuilderFactory(
+			e.links,
+			factory.NewLinksExecutorFactory(nil, 0, policy, nil, nil).Create,
+			actionFilter,
+			nil,
 		),
 		factory.NewExecutorBuilderFactory(
 			e.cmd,
 			factory.NewRunCommandExecutor,
 			actionFilter,
+			nil,
 		),
 	).Create()
 
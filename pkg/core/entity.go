@@ -1,6 +1,8 @@
 package core
 
 import (
+	"os"
+
 	"github.com/kozmod/progen/internal/entity"
 )
 
@@ -13,26 +15,68 @@ type (
 	File struct {
 		Path string
 		Data []byte
+		// OnExists is the policy of the existing file, [Config] policy is used when it is empty.
+		OnExists OnExists
+		// Mode is the permissions of the file (`0755`), the zero mode keeps the default permissions.
+		Mode os.FileMode
+		// Raw is true when the data is saved as is (without templating).
+		Raw bool
+	}
+
+	Dir struct {
+		Path string
+		// Mode is the permissions of the dir (`0700`), the zero mode keeps the default permissions.
+		Mode os.FileMode
 	}
 
+	// Link is the symbolic link to the target, the hard link is created when Hard is true.
+	Link struct {
+		Path   string
+		Target string
+		Hard   bool
+		// OnExists is the policy of the existing path, [Config] policy is used when it is empty.
+		OnExists OnExists
+	}
+
+	// OnExists is the policy of the existing file.
+	OnExists = entity.OnExists
+
 	Cmd      entity.Command
 	TargetFs entity.TargetFs
 )
 
+// Policies of the existing files.
+const (
+	OnExistsOverwrite = entity.OnExistsOverwrite
+	OnExistsSkip      = entity.OnExistsSkip
+	OnExistsError     = entity.OnExistsError
+	OnExistsBackup    = entity.OnExistsBackup
+	OnExistsAppend    = entity.OnExistsAppend
+	OnExistsPrepend   = entity.OnExistsPrepend
+	OnExistsPrompt    = entity.OnExistsPrompt
+)
+
 type Files entity.Action[[]File]
 
 func (c Files) add(e *Engin) {
 	if e != nil {
 		files := convert(c.Val, func(s File) entity.UndefinedFile {
 			return entity.UndefinedFile{
-				Path: s.Path,
-				Data: &s.Data,
+				Path:     s.Path,
+				Data:     &s.Data,
+				OnExists: s.OnExists,
+				Mode:     s.Mode,
+				Raw:      s.Raw,
 			}
 		})
 		e.files = append(e.files, entity.Action[[]entity.UndefinedFile]{
-			Name:     c.Name,
-			Val:      files,
-			Priority: c.Priority,
+			Name:      c.Name,
+			Val:       files,
+			Priority:  c.Priority,
+			DependsOn: c.DependsOn,
+			Parallel:  c.Parallel,
+			Before:    c.Before,
+			After:     c.After,
 		})
 	}
 }
@@ -42,6 +86,21 @@ func (c Files) WithPriority(priority int) Files {
 	return c
 }
 
+func (c Files) WithDependsOn(names ...string) Files {
+	c.DependsOn = names
+	return c
+}
+
+func (c Files) WithParallel(parallel bool) Files {
+	c.Parallel = parallel
+	return c
+}
+
+func (c Files) WithHooks(before, after []Cmd) Files {
+	c.Before, c.After = toCommands(before), toCommands(after)
+	return c
+}
+
 func FilesAction(name string, files ...File) Files {
 	return Files{
 		Name: name,
@@ -61,9 +120,13 @@ func (c Command) add(e *Engin) {
 			}
 		})
 		e.cmd = append(e.cmd, entity.Action[[]entity.Command]{
-			Name:     c.Name,
-			Val:      commands,
-			Priority: c.Priority,
+			Name:      c.Name,
+			Val:       commands,
+			Priority:  c.Priority,
+			DependsOn: c.DependsOn,
+			Parallel:  c.Parallel,
+			Before:    c.Before,
+			After:     c.After,
 		})
 	}
 }
@@ -73,6 +136,21 @@ func (c Command) WithPriority(priority int) Command {
 	return c
 }
 
+func (c Command) WithDependsOn(names ...string) Command {
+	c.DependsOn = names
+	return c
+}
+
+func (c Command) WithParallel(parallel bool) Command {
+	c.Parallel = parallel
+	return c
+}
+
+func (c Command) WithHooks(before, after []Cmd) Command {
+	c.Before, c.After = toCommands(before), toCommands(after)
+	return c
+}
+
 func CmdAction(name string, commands ...Cmd) Command {
 	return Command{
 		Name: name,
@@ -84,10 +162,16 @@ type Dirs entity.Action[[]string]
 
 func (d Dirs) add(e *Engin) {
 	if e != nil {
-		e.dirs = append(e.dirs, entity.Action[[]string]{
-			Name:     d.Name,
-			Val:      d.Val,
-			Priority: d.Priority,
+		e.dirs = append(e.dirs, entity.Action[[]entity.Dir]{
+			Name: d.Name,
+			Val: convert(d.Val, func(s string) entity.Dir {
+				return entity.Dir{Path: s}
+			}),
+			Priority:  d.Priority,
+			DependsOn: d.DependsOn,
+			Parallel:  d.Parallel,
+			Before:    d.Before,
+			After:     d.After,
 		})
 	}
 }
@@ -97,6 +181,36 @@ func (d Dirs) WithPriority(priority int) Dirs {
 	return d
 }
 
+func (d Dirs) WithDependsOn(names ...string) Dirs {
+	d.DependsOn = names
+	return d
+}
+
+func (d Dirs) WithParallel(parallel bool) Dirs {
+	d.Parallel = parallel
+	return d
+}
+
+func (d Dirs) WithHooks(before, after []Cmd) Dirs {
+	d.Before, d.After = toCommands(before), toCommands(after)
+	return d
+}
+
+// WithMode returns the action, which creates the dirs with the permissions.
+func (d Dirs) WithMode(mode os.FileMode) DirsMode {
+	return DirsMode{
+		Name: d.Name,
+		Val: convert(d.Val, func(s string) Dir {
+			return Dir{Path: s, Mode: mode}
+		}),
+		Priority:  d.Priority,
+		DependsOn: d.DependsOn,
+		Parallel:  d.Parallel,
+		Before:    d.Before,
+		After:     d.After,
+	}
+}
+
 func DirsAction(name string, dirs ...string) Dirs {
 	return Dirs{
 		Name: name,
@@ -104,14 +218,60 @@ func DirsAction(name string, dirs ...string) Dirs {
 	}
 }
 
+// DirsMode is the dirs action with the permissions of the dirs.
+type DirsMode entity.Action[[]Dir]
+
+func (d DirsMode) add(e *Engin) {
+	if e != nil {
+		e.dirs = append(e.dirs, entity.Action[[]entity.Dir]{
+			Name: d.Name,
+			Val: convert(d.Val, func(s Dir) entity.Dir {
+				return entity.Dir{
+					Path: s.Path,
+					Mode: s.Mode,
+				}
+			}),
+			Priority:  d.Priority,
+			DependsOn: d.DependsOn,
+			Parallel:  d.Parallel,
+			Before:    d.Before,
+			After:     d.After,
+		})
+	}
+}
+
+func (d DirsMode) WithPriority(priority int) DirsMode {
+	d.Priority = priority
+	return d
+}
+
+func (d DirsMode) WithDependsOn(names ...string) DirsMode {
+	d.DependsOn = names
+	return d
+}
+
+func (d DirsMode) WithParallel(parallel bool) DirsMode {
+	d.Parallel = parallel
+	return d
+}
+
+func (d DirsMode) WithHooks(before, after []Cmd) DirsMode {
+	d.Before, d.After = toCommands(before), toCommands(after)
+	return d
+}
+
 type Rm entity.Action[[]string]
 
 func (r Rm) add(e *Engin) {
 	if e != nil {
 		e.rm = append(e.rm, entity.Action[[]string]{
-			Name:     r.Name,
-			Val:      r.Val,
-			Priority: r.Priority,
+			Name:      r.Name,
+			Val:       r.Val,
+			Priority:  r.Priority,
+			DependsOn: r.DependsOn,
+			Parallel:  r.Parallel,
+			Before:    r.Before,
+			After:     r.After,
 		})
 	}
 }
@@ -121,6 +281,21 @@ func (r Rm) WithPriority(priority int) Rm {
 	return r
 }
 
+func (r Rm) WithDependsOn(names ...string) Rm {
+	r.DependsOn = names
+	return r
+}
+
+func (r Rm) WithParallel(parallel bool) Rm {
+	r.Parallel = parallel
+	return r
+}
+
+func (r Rm) WithHooks(before, after []Cmd) Rm {
+	r.Before, r.After = toCommands(before), toCommands(after)
+	return r
+}
+
 func RmAction(name string, rm ...string) Rm {
 	return Rm{
 		Name: name,
@@ -132,10 +307,17 @@ type FsModify entity.Action[[]string]
 
 func (f FsModify) add(e *Engin) {
 	if e != nil {
-		e.fsModify = append(e.fsModify, entity.Action[[]string]{
-			Name:     f.Name,
-			Val:      f.Val,
-			Priority: f.Priority,
+		dirs := convert(f.Val, func(s string) entity.FsDir {
+			return entity.FsDir{Path: s}
+		})
+		e.fsModify = append(e.fsModify, entity.Action[[]entity.FsDir]{
+			Name:      f.Name,
+			Val:       dirs,
+			Priority:  f.Priority,
+			DependsOn: f.DependsOn,
+			Parallel:  f.Parallel,
+			Before:    f.Before,
+			After:     f.After,
 		})
 	}
 }
@@ -145,6 +327,21 @@ func (f FsModify) WithPriority(priority int) FsModify {
 	return f
 }
 
+func (f FsModify) WithDependsOn(names ...string) FsModify {
+	f.DependsOn = names
+	return f
+}
+
+func (f FsModify) WithParallel(parallel bool) FsModify {
+	f.Parallel = parallel
+	return f
+}
+
+func (f FsModify) WithHooks(before, after []Cmd) FsModify {
+	f.Before, f.After = toCommands(before), toCommands(after)
+	return f
+}
+
 func FsModifyAction(name string, fs ...string) FsModify {
 	return FsModify{
 		Name: name,
@@ -160,12 +357,17 @@ func (f FsSave) add(e *Engin) {
 			return entity.TargetFs{
 				TargetDir: s.TargetDir,
 				Fs:        s.Fs,
+				Raw:       s.Raw,
 			}
 		})
 		e.fsSave = append(e.fsSave, entity.Action[[]entity.TargetFs]{
-			Name:     f.Name,
-			Val:      fs,
-			Priority: f.Priority,
+			Name:      f.Name,
+			Val:       fs,
+			Priority:  f.Priority,
+			DependsOn: f.DependsOn,
+			Parallel:  f.Parallel,
+			Before:    f.Before,
+			After:     f.After,
 		})
 	}
 }
@@ -175,6 +377,21 @@ func (f FsSave) WithPriority(priority int) FsSave {
 	return f
 }
 
+func (f FsSave) WithDependsOn(names ...string) FsSave {
+	f.DependsOn = names
+	return f
+}
+
+func (f FsSave) WithParallel(parallel bool) FsSave {
+	f.Parallel = parallel
+	return f
+}
+
+func (f FsSave) WithHooks(before, after []Cmd) FsSave {
+	f.Before, f.After = toCommands(before), toCommands(after)
+	return f
+}
+
 func FsSaveAction(name string, fs ...TargetFs) FsSave {
 	return FsSave{
 		Name: name,
@@ -182,6 +399,63 @@ func FsSaveAction(name string, fs ...TargetFs) FsSave {
 	}
 }
 
+type Links entity.Action[[]Link]
+
+func (l Links) add(e *Engin) {
+	if e != nil {
+		links := convert(l.Val, func(s Link) entity.Link {
+			return entity.Link{
+				Path:     s.Path,
+				Target:   s.Target,
+				Hard:     s.Hard,
+				OnExists: s.OnExists,
+			}
+		})
+		e.links = append(e.links, entity.Action[[]entity.Link]{
+			Name:      l.Name,
+			Val:       links,
+			Priority:  l.Priority,
+			DependsOn: l.DependsOn,
+			Parallel:  l.Parallel,
+			Before:    l.Before,
+			After:     l.After,
+		})
+	}
+}
+
+func (l Links) WithPriority(priority int) Links {
+	l.Priority = priority
+	return l
+}
+
+func (l Links) WithDependsOn(names ...string) Links {
+	l.DependsOn = names
+	return l
+}
+
+func (l Links) WithParallel(parallel bool) Links {
+	l.Parallel = parallel
+	return l
+}
+
+func (l Links) WithHooks(before, after []Cmd) Links {
+	l.Before, l.After = toCommands(before), toCommands(after)
+	return l
+}
+
+func LinksAction(name string, links ...Link) Links {
+	return Links{
+		Name: name,
+		Val:  links,
+	}
+}
+
+func toCommands(cmds []Cmd) []entity.Command {
+	return convert(cmds, func(s Cmd) entity.Command {
+		return entity.Command(s)
+	})
+}
+
 func convert[S any, T any](s []S, fn func(s S) T) []T {
 	res := make([]T, len(s))
 	for i, val := range s {
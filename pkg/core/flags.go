@@ -11,6 +11,11 @@ import (
 // Config for the [Engin].
 type Config internalFlags.DefaultFlags
 
+// vars returns the template variables: variables files merged in order and overridden by the template variables.
+func (c *Config) vars() (map[string]any, error) {
+	return (*internalFlags.DefaultFlags)(c).Vars()
+}
+
 // AddConfigFlagSet adds flags to [*flag.FlagSet] and creates [Engin] config.
 func AddConfigFlagSet(fs *flag.FlagSet) *Config {
 	f := internalFlags.NewDefaultFlags(fs)